@@ -2,6 +2,10 @@ package gateway
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"fintech-capstone/m/v2/cmd/api-gateway/stubs"
@@ -11,6 +15,8 @@ import (
 	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
 	"fintech-capstone/m/v2/internal/limiter"
 	"fintech-capstone/m/v2/internal/platform"
+	"fintech-capstone/m/v2/internal/platform/concurrency"
+	"fintech-capstone/m/v2/internal/worker_pool"
 )
 
 // BuildGateway constructs the API Gateway with all its handlers and dependencies.
@@ -19,7 +25,7 @@ import (
 func BuildGateway(logger platform.Logger) *entrypoint.Gateway {
 	_, idemp, dispatch, metrics := stubs.BuildTransfer()
 
-	lim := limiter.New(context.Background(), limiter.Config{
+	lim, err := limiter.New(context.Background(), limiter.Config{
 		PerClient: limiter.PerClientConfig{
 			RatePerSec:    50,  // 50 rps per client
 			Burst:         100, // allow short spikes
@@ -34,8 +40,46 @@ func BuildGateway(logger platform.Logger) *entrypoint.Gateway {
 		NumShards:       64,
 		CleanupInterval: time.Minute,
 	})
+	if err != nil {
+		logger.Fatal(fmt.Errorf("rate limiter: %w", err))
+	}
+	// Per-client plans from config/rate_limit_plans.json, reloaded on SIGHUP or
+	// file change; unlisted clients get the default plan.
+	const plansPath = "config/rate_limit_plans.json"
+	if plans, err := limiter.LoadPlans(plansPath); err != nil {
+		logger.Warn("rate limit plans not loaded", platform.Field{Key: "error", Value: err.Error()})
+	} else if _, err := lim.SetPlans(plans); err != nil {
+		logger.Warn("rate limit plans not applied", platform.Field{Key: "error", Value: err.Error()})
+	}
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go limiter.WatchPlans(context.Background(), lim, plansPath, 5*time.Second, hup, logger)
+
+	// Bulkheads: source accounts hash into 4 independent pools, so one hot
+	// segment cannot exhaust the workers of the others.
+	segments := []string{"accounts-0", "accounts-1", "accounts-2", "accounts-3"}
+	bulkheads := make([]worker_pool.BulkheadConfig, len(segments))
+	for i, name := range segments {
+		bulkheads[i] = worker_pool.BulkheadConfig{Name: name, Pool: worker_pool.Config{
+			Workers: 2,
+			Classes: worker_pool.DefaultClasses, // interactive 8 : bulk 3 : internal 1
+			Shedding: worker_pool.ShedConfig{
+				Target:     50 * time.Millisecond,
+				Interval:   100 * time.Millisecond,
+				RetryAfter: time.Second,
+			},
+		}}
+	}
+	pool := worker_pool.NewBulkheads(context.Background(), worker_pool.HashSegments(segments...), bulkheads, dispatch.Submit)
+
+	// Ordered execution: transfers from one account run FIFO, one at a time.
+	ordered := worker_pool.NewMailboxes(context.Background(), worker_pool.MailboxConfig{
+		MailboxSize: 64,
+		TTL:         10 * time.Minute,
+	}, pool)
+
 	// Use case (app layer)
-	uc := app.NewTransferService(dispatch, metrics, logger)
+	uc := app.NewTransferService(ordered, metrics, logger)
 
 	// Endpoints provider (base handlers only)
 
@@ -50,9 +94,19 @@ func BuildGateway(logger platform.Logger) *entrypoint.Gateway {
 	// Build composed handlers per endpoint, no repeated options
 	submitH := compTR.Build(uc.SubmitTransfer)
 
+	// Adaptive ingress limit (replaces MaxInFlight(1024)).
+	// Shadow: true only logs what it would reject.
+	inFlight := concurrency.New(concurrency.Config{
+		Algorithm:    concurrency.Vegas,
+		InitialLimit: 64,
+		MinLimit:     8,
+		MaxLimit:     1024,
+	}, logger)
+
 	// Mount on gateway (kept dumb)
-	gw := entrypoint.NewGateway(metrics, dispatch, logger,
+	gw := entrypoint.NewGateway(metrics, ordered, logger,
 		entrypoint.WithTransfer(submitH),
+		entrypoint.WithConcurrencyLimiter(inFlight),
 		// entrypoint.WithTransferCancel(cancelH), - example more endpoints
 	)
 	return gw
@@ -1,8 +1,8 @@
 package http_api
 
 import (
-	"fintech-capstone/m/v2/cmd/api-gateway/gateway"
 	http_transport "fintech-capstone/m/v2/internal/api_gateway/adapters/inbound/http"
+	"fintech-capstone/m/v2/internal/api_gateway/entrypoint"
 	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
 	"fintech-capstone/m/v2/internal/platform"
 	"fmt"
@@ -10,8 +10,8 @@ import (
 )
 
 // BuildServer builds and returns an HTTP server for the API Gateway.
-func BuildServer(logger platform.Logger) inbound.Server {
-	handler := http_transport.NewRouter(gateway.BuildGateway(logger), logger)
+func BuildServer(gw *entrypoint.Gateway, logger platform.Logger) inbound.Server {
+	handler := http_transport.NewRouter(gw, logger)
 
 	httpSrv, err := http_transport.NewHTTPServer(
 		":8080", handler,
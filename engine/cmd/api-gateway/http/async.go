@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"fintech-capstone/m/v2/internal/api_gateway/app"
+	"fintech-capstone/m/v2/internal/api_gateway/app/policy"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+	"fintech-capstone/m/v2/internal/platform/http_kit/writer"
+
+	"github.com/race-conditioned/hexa/fusion/dt"
+)
+
+// asyncTransferHTTP serves POST /transfer with `Prefer: respond-async`: the transfer
+// is admitted (idempotency, rate limit), answered with 202 and a status URL, and
+// completed in the background. A duplicate is answered from its first submission:
+// 202 while that is pending, its stored response with 200 once it has finished.
+// dt only speaks synchronous JSON, so this route is plain net/http.
+func asyncTransferHTTP(svc *app.AsyncTransferService, plugins policy.Plugins) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cmd, err := decodeTransfer(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		// SubmitAsync detaches the completion from the request (WithoutCancel).
+		// The priority stage grants the asked class only within the client's tier.
+		ctx := outbound.WithRequestedPriority(r.Context(), r.Header.Get(inbound.PriorityClassHeader))
+		ctx, report := outbound.WithLimitReport(ctx)
+		res, err := svc.SubmitAsync(plugins.WithContext(ctx), dt.DefaultMeta(r), cmd)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if d, ok := report.Decision(); ok {
+			writer.RateLimit(w, d.Limit, d.Remaining, d.Reset)
+		}
+
+		statusURL := inbound.TransferStatusURL(res.TransactionID())
+		w.Header().Set("Location", statusURL)
+		if inbound.Replayed(res) {
+			w.Header().Set(inbound.IdempotentReplayedHeader, "true")
+			if !svc.Pending(res.TransactionID()) {
+				writer.JSON(w, http.StatusOK, res.Response())
+				return
+			}
+		}
+		writer.JSON(w, http.StatusAccepted, inbound.TransferAcceptedResponse{
+			TransactionID: res.TransactionID().String(),
+			Status:        inbound.ResultStatusAccepted.String(),
+			StatusURL:     statusURL,
+		})
+	}
+}
+
+// transferStatusHTTP serves GET /transfers/{id} for asynchronously submitted transfers,
+// to the client (X-Client-ID) that submitted them.
+func transferStatusHTTP(svc *app.AsyncTransferService, plugins policy.Plugins) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := svc.Status(plugins.WithContext(r.Context()), dt.DefaultMeta(r), inbound.NewTransferStatusCommand(r.PathValue("id")))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writer.JSON(w, http.StatusOK, res.Response())
+	}
+}
+
+// writeError maps application errors like adapters/inbound/http does.
+func writeError(w http.ResponseWriter, err error) {
+	e := apperr.As(err)
+	writer.RetryAfter(w, e.RetryAfter)
+	var limit outbound.LimitExceeded
+	if errors.As(e.Err, &limit) {
+		writer.RateLimit(w, limit.Limit, limit.Remaining, limit.Reset)
+	}
+	if e.Replayed {
+		w.Header().Set(inbound.IdempotentReplayedHeader, "true")
+	}
+	switch e.Code {
+	case apperr.CodeInvalid:
+		writer.Error(w, http.StatusBadRequest, e.Msg)
+	case apperr.CodeRateLimited:
+		writer.Error(w, http.StatusTooManyRequests, e.Msg)
+	case apperr.CodeTimeout:
+		writer.Error(w, http.StatusGatewayTimeout, e.Msg)
+	case apperr.CodeNotFound:
+		writer.Error(w, http.StatusNotFound, e.Msg)
+	case apperr.CodeConflict, apperr.CodeInProgress:
+		writer.Error(w, http.StatusConflict, e.Msg)
+	case apperr.CodePayloadTooLarge:
+		writer.Error(w, http.StatusRequestEntityTooLarge, e.Msg)
+	case apperr.CodeOverloaded:
+		writer.Error(w, http.StatusServiceUnavailable, e.Msg)
+	default:
+		writer.Error(w, http.StatusInternalServerError, e.Msg)
+	}
+}
+
+// decodeTransfer reads a TransferCommandHTTP body. The idempotency key may come
+// from the body or the Idempotency-Key header; if both are set they must match.
+func decodeTransfer(r *http.Request) (inbound.TransferCommand, error) {
+	var dto inbound.TransferCommandHTTP
+	body := &bodyReader{ReadCloser: r.Body}
+	dec := json.NewDecoder(body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&dto); err != nil {
+		return inbound.TransferCommand{}, decodeError(body.err)
+	}
+	key, err := inbound.ResolveIdempotencyKey(r.Header.Get(inbound.IdempotencyKeyHeader), dto.IdempotencyKey)
+	if err != nil {
+		return inbound.TransferCommand{}, apperr.Invalid(err.Error())
+	}
+	dto.IdempotencyKey = key
+	return dto.ToCommand().(inbound.TransferCommand), nil
+}
+
+// bodyReader records the first error reading a request body, so a failed decode
+// can tell a body that could not be read from one that is not valid JSON.
+type bodyReader struct {
+	io.ReadCloser
+	err error
+}
+
+// Read implements io.Reader.
+func (b *bodyReader) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF && b.err == nil {
+		b.err = err
+	}
+	return n, err
+}
+
+// decodeError maps a failed decode of a request body whose read failed with
+// readErr, if it did. Only a body that was read whole and is not a valid payload
+// is a 400; a body over the size limit is a 413, any other read failure a 500.
+func decodeError(readErr error) error {
+	var maxErr *http.MaxBytesError
+	switch {
+	case readErr == nil:
+		return apperr.Invalid("invalid JSON payload")
+	case errors.As(readErr, &maxErr):
+		return apperr.PayloadTooLarge("request body too large")
+	default:
+		return apperr.Wrap(apperr.CodeInternal, "failed to read request body", readErr)
+	}
+}
@@ -2,25 +2,52 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"fintech-capstone/m/v2/cmd/api-gateway/stubs"
+	"fintech-capstone/m/v2/internal/admin_allowlist"
+	grpc_transport "fintech-capstone/m/v2/internal/api_gateway/adapters/inbound/grpc"
+	pb "fintech-capstone/m/v2/internal/api_gateway/adapters/inbound/grpc/proto"
+	sse_transport "fintech-capstone/m/v2/internal/api_gateway/adapters/inbound/sse"
 	"fintech-capstone/m/v2/internal/api_gateway/app"
 	"fintech-capstone/m/v2/internal/api_gateway/app/policy"
+	"fintech-capstone/m/v2/internal/api_gateway/contracts"
 	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/circuit_breaker"
+	"fintech-capstone/m/v2/internal/commit_log"
+	"fintech-capstone/m/v2/internal/dead_letter"
+	"fintech-capstone/m/v2/internal/event_stream"
+	"fintech-capstone/m/v2/internal/idempotency_store"
+	"fintech-capstone/m/v2/internal/job_journal"
+	"fintech-capstone/m/v2/internal/kv_store"
 	"fintech-capstone/m/v2/internal/limiter"
+	"fintech-capstone/m/v2/internal/platform"
 	zap_adapter "fintech-capstone/m/v2/internal/platform/adapters/zap"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+	"fintech-capstone/m/v2/internal/platform/concurrency"
+	"fintech-capstone/m/v2/internal/platform/http_kit/middleware"
+	"fintech-capstone/m/v2/internal/platform/http_kit/writer"
+	"fintech-capstone/m/v2/internal/retry_budget"
+	"fintech-capstone/m/v2/internal/transfer_status"
+	"fintech-capstone/m/v2/internal/webhook"
+	"fintech-capstone/m/v2/internal/worker_pool"
 
 	"github.com/race-conditioned/hexa/endurance"
 	"github.com/race-conditioned/hexa/fusion/dt"
 	"github.com/race-conditioned/hexa/fusion/intake"
 	"github.com/race-conditioned/hexa/horizon"
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
 	"github.com/race-conditioned/hexa/symphony"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 )
 
 // main is the entry point for the API Gateway HTTP server.
@@ -36,9 +63,27 @@ func main() {
 
 	// httpSrv := http_api.BuildServer(logger)
 
-	_, idemp, dispatch, metrics := stubs.BuildTransfer()
+	_, _, dispatch, metrics := stubs.BuildTransfer()
 
-	lim := limiter.New(context.Background(), limiter.Config{
+	// Idempotency: transfers are replayable for 24h; at most 100k results are kept,
+	// least recently used evicted first. Routes listed with 0 are never stored.
+	// Concurrent duplicates wait for the first request instead of executing.
+	idemp := idempotency_store.New(context.Background(), idempotency_store.Config[hexa_inbound.Result]{
+		TTL: 24 * time.Hour,
+		Routes: map[string]time.Duration{
+			"POST /transfer": 24 * time.Hour,
+		},
+		// Rejected transfers replay their rejection; timeouts and overload do not.
+		Failures: map[string][]apperr.Code{
+			"POST /transfer": {apperr.CodeInvalid, apperr.CodeConflict},
+		},
+		MaxEntries:      100_000,
+		NumShards:       64,
+		CleanupInterval: time.Minute,
+		ClaimTTL:        time.Minute, // well above the 2s route timeout; async claims end at admission
+	})
+
+	lim, err := limiter.New(context.Background(), limiter.Config{
 		PerClient: limiter.PerClientConfig{
 			RatePerSec:    50,  // 50 rps per client
 			Burst:         100, // allow short spikes
@@ -52,14 +97,167 @@ func main() {
 		},
 		NumShards:       64,
 		CleanupInterval: time.Minute,
+		// Large transfers draw the budget down faster: $10k+ costs 3 tokens,
+		// $1M+ costs 10, the smallest plan's (free) whole burst. New and SetPlans
+		// reject bursts that cannot hold a route's dearest request.
+		Costs: map[string]limiter.RouteCost{
+			"POST /transfer": {Base: 1, Bands: []limiter.AmountBand{
+				{MinCents: 1_000_000, Cost: 2},
+				{MinCents: 100_000_000, Cost: 9},
+			}},
+		},
 	})
+	if err != nil {
+		log.Fatal(fmt.Errorf("rate limiter: %w", err))
+	}
+
+	// Rate-limit plans (free, standard, partner, internal) assign per-client limits;
+	// unlisted clients get the default plan. The file is reloaded on SIGHUP or when
+	// it changes, keeping every client's tokens. Without it, everyone gets PerClient.
+	const plansPath = "config/rate_limit_plans.json"
+	if plans, err := limiter.LoadPlans(plansPath); err != nil {
+		logger.Warn("rate limit plans not loaded", platform.Field{Key: "error", Value: err.Error()})
+	} else if _, err := lim.SetPlans(plans); err != nil {
+		logger.Warn("rate limit plans not applied", platform.Field{Key: "error", Value: err.Error()})
+	}
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go limiter.WatchPlans(context.Background(), lim, plansPath, 5*time.Second, hup, logger)
+
+	// Shared limits: replicas enforce the plans above together with GCRA over an
+	// atomic KV store. The in-process store stands in for a shared one (e.g. Redis)
+	// in dev. While the store fails, its breaker opens and each replica falls back
+	// to lim.
+	limitStore := kv_store.NewMemory(context.Background(), kv_store.Config{})
+	limitStoreBreaker := circuit_breaker.New(circuit_breaker.Config{
+		Name:        "rate_limit_store",
+		MinRequests: 20,
+		OpenFor:     2 * time.Second,
+	}, logger)
+	sharedLim := limiter.NewDistributed(limiter.DistributedConfig{
+		Store:   limitStore,
+		Timeout: 50 * time.Millisecond,
+		Breaker: limitStoreBreaker,
+	}, lim)
+
+	// Bulkheads: source accounts hash into 4 independent pools, so one hot
+	// segment cannot exhaust the workers of the others.
+	segments := []string{"accounts-0", "accounts-1", "accounts-2", "accounts-3"}
+	bulkheads := make([]worker_pool.BulkheadConfig, len(segments))
+	for i, name := range segments {
+		bulkheads[i] = worker_pool.BulkheadConfig{Name: name, Pool: worker_pool.Config{
+			Workers: 2,
+			Classes: worker_pool.DefaultClasses, // interactive 8 : bulk 3 : internal 1
+			// Under a standing queue, shed jobs that waited past 50ms (bulk ~19ms,
+			// internal ~6ms) and serve newest first; callers get 503 + Retry-After.
+			Shedding: worker_pool.ShedConfig{
+				Target:     50 * time.Millisecond,
+				Interval:   100 * time.Millisecond,
+				RetryAfter: time.Second,
+			},
+		}}
+	}
+	// Commit log: every committed transfer by client and key, on disk. A dead letter
+	// is replayed only if the log confirms its transfer never committed.
+	commits, err := commit_log.New(context.Background(), commit_log.Config{Path: "data/commits.log"})
+	if err != nil {
+		log.Fatal(fmt.Errorf("commit log: %w", err))
+	}
+	execute := func(ctx context.Context, cmd inbound.TransferCommand) (inbound.TransferResult, error) {
+		res, err := dispatch.Submit(ctx, cmd)
+		if err == nil {
+			clientID, _ := outbound.ClientIDFrom(ctx)
+			if cerr := commits.Committed(clientID, cmd.IdempotencyKey()); cerr != nil {
+				logger.Error(cerr, platform.Field{Key: "component", Value: "commit_log"})
+			}
+		}
+		return res, err
+	}
+	pool := worker_pool.NewBulkheads(context.Background(), worker_pool.HashSegments(segments...), bulkheads, execute)
+
+	// Ordered execution: transfers from one account run FIFO, one at a time.
+	ordered := worker_pool.NewMailboxes(context.Background(), worker_pool.MailboxConfig{
+		MailboxSize: 64,
+		TTL:         10 * time.Minute,
+	}, pool)
+
+	// Queued jobs survive restarts: drained and saved on shutdown, resumed on boot.
+	journal, err := job_journal.NewFileStore("data/pending_jobs.json")
+	if err != nil {
+		log.Fatal(fmt.Errorf("job journal: %w", err))
+	}
+
+	tiers := worker_pool.Tiers{
+		Clients: map[string]outbound.PriorityClass{
+			"payouts-batch":  outbound.PriorityBulk,
+			"ledger-sweeper": outbound.PriorityInternal,
+		},
+		Default: outbound.PriorityInteractive,
+	}
+	// Admins may act on other clients' dead letters, plans and events. Granted by
+	// name only: a client's priority tier is scheduling, not authorization.
+	admins := admin_allowlist.New("ledger-sweeper")
+
+	// Dead letters: transfers that fail for internal reasons are parked on disk for replay.
+	deadLetters, err := dead_letter.NewFileStore("data/dead_letters.json")
+	if err != nil {
+		log.Fatal(fmt.Errorf("dead letter store: %w", err))
+	}
+
+	// Webhooks: signed transfer lifecycle events, delivered durably in the background.
+	hooks, err := webhook.New(context.Background(), webhook.Config{
+		Path:             "data/webhooks.json",
+		MaxAttempts:      12,
+		BaseBackoff:      time.Second,
+		MaxBackoff:       time.Hour,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+		DisableAfter:     50,
+	}, logger)
+	if err != nil {
+		log.Fatal(fmt.Errorf("webhooks: %w", err))
+	}
 
-	uc := app.NewTransferService(dispatch, metrics, logger)
+	// Live stream: the same events, fanned out to SSE and gRPC watchers.
+	stream := event_stream.New(event_stream.Config{
+		BufferSize:  64,
+		ReplaySize:  1024,
+		MaxWatchers: 1024,
+	})
+
+	// Circuit breaker around the dispatcher: opens at a 50% failure rate over 10s
+	// (at least 20 calls), fails fast for 5s, then closes after 3 good probes.
+	dispatcherBreaker := circuit_breaker.New(circuit_breaker.Config{
+		Name:        "dispatcher",
+		FailureRate: 0.5,
+		MinRequests: 20,
+		Window:      10 * time.Second,
+		OpenFor:     5 * time.Second,
+		Probes:      3,
+	}, logger)
+
+	// Retries of transient failures: at most 2 per transfer, funded by 10% of
+	// traffic (plus 1/s), with 20ms..500ms full-jitter backoff.
+	retries := retry_budget.New(retry_budget.Config{
+		MaxRetries:   2,
+		Ratio:        0.1,
+		MinPerSecond: 1,
+		Burst:        20,
+		BaseBackoff:  20 * time.Millisecond,
+		MaxBackoff:   500 * time.Millisecond,
+	})
+
+	uc := app.NewTransferService(ordered, metrics, logger)
 	plugins := policy.NewPluginsImpl(
 		context.Background(),
 		metrics,
-		lim,
+		sharedLim,
 		idemp,
+		tiers,
+		deadLetters,
+		event_stream.Fanout{hooks, stream},
+		dispatcherBreaker,
+		retries,
 	)
 	gw := horizon.NewGateway[policy.Plugins](plugins)
 
@@ -73,40 +271,139 @@ func main() {
 
 	idempotency := symphony.PolicyStage("idempotency")
 	rateLimit := symphony.PolicyStage("rate_limit")
+	priority := symphony.PolicyStage("priority")
 	timeout := symphony.PolicyStage("timeout")
 	latency := symphony.PolicyStage("latency")
+	events := symphony.PolicyStage("events")
+	deadLetter := symphony.PolicyStage("dead_letter")
+	retry := symphony.PolicyStage("retry")
+	circuitBreaker := symphony.PolicyStage("circuit_breaker")
 
 	composer := symphony.New[policy.Plugins](symphony.Order(), symphony.Order())
 
 	DefaultPolicyOrder := []symphony.PolicyStage{
 		"idempotency",
 		"rate_limit",
+		"priority",
 		"timeout",
 		"latency",
+		"events",
+		"dead_letter",
+		"retry",
+		"circuit_breaker",
 	}
 	mid := symphony.Order(DefaultPolicyOrder...)
 
-	transferComposition := symphony.Compose(
-		composer,
-		mid,
+	transferPolicies := []symphony.CompositionOption[policy.Plugins, inbound.TransferCommand, inbound.TransferResult]{
 		symphony.WithPolicy(rateLimit, symphony.Lift[policy.Plugins, inbound.TransferCommand, inbound.TransferResult](policy.RateLimit)),
+		symphony.WithPolicy(priority, symphony.Lift[policy.Plugins, inbound.TransferCommand, inbound.TransferResult](policy.Priority)),
 		symphony.WithPolicy(timeout, symphony.Lift[policy.Plugins, inbound.TransferCommand, inbound.TransferResult](policy.Timeout)),
 		symphony.WithPolicy(latency, symphony.Lift[policy.Plugins, inbound.TransferCommand, inbound.TransferResult](policy.ObserveLatency)),
 		symphony.WithPolicy(idempotency, symphony.LiftCap[policy.Plugins, inbound.TransferCommand, inbound.TransferResult](policy.Idempotency)),
-	)
+		symphony.WithPolicy(events, policy.Events),
+		symphony.WithPolicy(deadLetter, policy.DeadLetter),
+		// Retry is only lifted for commands that implement inbound.IdempotentCommand.
+		symphony.WithPolicy(retry, symphony.LiftCap[policy.Plugins, inbound.TransferCommand, inbound.TransferResult](policy.Retry)),
+		symphony.WithPolicy(circuitBreaker, symphony.Lift[policy.Plugins, inbound.TransferCommand, inbound.TransferResult](policy.CircuitBreaker)),
+	}
+	transferComposition := symphony.Compose(composer, mid, transferPolicies...)
 
 	h := transferComposition.Wrap(endurance.Transport(uc.SubmitTransfer, nil, nil))
 
+	// Async (Prefer: respond-async) transfers are admitted before the 202 by the
+	// idempotency and rate-limit stages, then complete in the background through the
+	// rest, minus the synchronous timeout; AsyncConfig bounds them instead.
+	admitOrder := symphony.Order("idempotency", "rate_limit")
+	admission := symphony.Compose(composer, admitOrder, transferPolicies...)
+	completeOrder := symphony.Order("priority", "latency", "events", "dead_letter", "retry", "circuit_breaker")
+	asyncH := symphony.Compose(composer, completeOrder, transferPolicies...).Wrap(endurance.Transport(uc.SubmitTransfer, nil, nil))
+	statuses := transfer_status.New(context.Background(), transfer_status.Config{TTL: time.Hour})
+	async := app.NewAsyncTransferService(statuses, admission.Wrap, asyncH, app.AsyncConfig{
+		MaxPending:        1024,
+		CompletionTimeout: 30 * time.Second,
+	}, logger)
+
+	// Jobs saved at the last shutdown resume through the chain minus the rate limit,
+	// which charged them when they were submitted. Their callers were answered 202
+	// with a transaction ID, polled like async transfers.
+	resumeOrder := symphony.Order("idempotency", "priority", "timeout", "latency", "events", "dead_letter", "retry", "circuit_breaker")
+	resumeH := symphony.Compose(composer, resumeOrder, transferPolicies...).Wrap(endurance.Transport(uc.SubmitTransfer, nil, nil))
+	recovery := app.NewQueueRecovery(ordered, journal, resumeH, statuses, logger)
+
 	gw.RegisterHandler("transfer", horizon.Adapt(h))
 
+	// Dead-letter admin: replays go through h with the original idempotency key, once
+	// the commit log confirms the transfer never committed.
+	dlq := app.NewDeadLetterService(deadLetters, commits, h, admins, logger)
+	gw.RegisterHandler("deadletters/list", horizon.Adapt(dlq.List))
+	gw.RegisterHandler("deadletters/inspect", horizon.Adapt(dlq.Inspect))
+	gw.RegisterHandler("deadletters/replay", horizon.Adapt(dlq.Replay))
+	gw.RegisterHandler("deadletters/discard", horizon.Adapt(dlq.Discard))
+
+	// Webhook subscriptions, scoped to the calling client (X-Client-ID).
+	webhooks := app.NewWebhookService(hooks, logger)
+	gw.RegisterHandler("webhooks/subscribe", horizon.Adapt(webhooks.Subscribe))
+	gw.RegisterHandler("webhooks/unsubscribe", horizon.Adapt(webhooks.Unsubscribe))
+	gw.RegisterHandler("webhooks/enable", horizon.Adapt(webhooks.Enable))
+	gw.RegisterHandler("webhooks/list", horizon.Adapt(webhooks.List))
+	gw.RegisterHandler("webhooks/deliveries", horizon.Adapt(webhooks.Deliveries))
+
+	// Rate-limit plan in effect for a client (default: the caller).
+	plans := app.NewRateLimitPlanService(sharedLim, admins)
+	gw.RegisterHandler("limits/plan", horizon.Adapt(plans.Plan))
+
+	// MaxInFlight stays 0: the adaptive limit below replaces a static cap.
 	spec := intake.Spec{}
 
+	// Adaptive ingress limit (Vegas). Shadow: true only logs what it would reject.
+	inFlight := concurrency.New(concurrency.Config{
+		Algorithm:    concurrency.Vegas,
+		InitialLimit: 64,
+		MinLimit:     8,
+		MaxLimit:     1024,
+	}, logger)
+
 	routes := []dt.Route[policy.Plugins]{
-		jsonRoute[inbound.TransferCommandHTTP]("transfer"),
+		jsonRoute[inbound.DeadLetterListCommandHTTP]("deadletters/list"),
+		jsonRoute[inbound.DeadLetterCommandHTTP]("deadletters/inspect"),
+		jsonRoute[inbound.DeadLetterCommandHTTP]("deadletters/replay"),
+		jsonRoute[inbound.DeadLetterCommandHTTP]("deadletters/discard"),
+		jsonRoute[inbound.WebhookSubscribeCommandHTTP]("webhooks/subscribe"),
+		jsonRoute[inbound.WebhookCommandHTTP]("webhooks/unsubscribe"),
+		jsonRoute[inbound.WebhookCommandHTTP]("webhooks/enable"),
+		jsonRoute[inbound.WebhookCommandHTTP]("webhooks/list"),
+		jsonRoute[inbound.WebhookCommandHTTP]("webhooks/deliveries"),
+		jsonRoute[inbound.RateLimitPlanCommandHTTP]("limits/plan"),
 	}
 
 	fusion := dt.NewFusion[policy.Plugins](plugins, spec, gw, routes)
-	router := fusion.Build()
+	mux := http.NewServeMux()
+	mux.Handle("/", fusion.Build())
+	mux.HandleFunc("POST /transfer", transferHTTP(gw, plugins))
+	mux.HandleFunc("GET /transfers/{id}", transferStatusHTTP(async, plugins))
+	mux.HandleFunc("GET "+sse_transport.StreamPath, sse_transport.TransferStream(stream, admins, 15*time.Second))
+	mux.HandleFunc("GET /metrics/concurrency", func(w http.ResponseWriter, _ *http.Request) {
+		writer.JSON(w, http.StatusOK, contracts.NewConcurrencySnapshot(inFlight.Stats()))
+	})
+	mux.HandleFunc("GET /metrics/idempotency", func(w http.ResponseWriter, _ *http.Request) {
+		writer.JSON(w, http.StatusOK, idemp.Snapshot())
+	})
+	mux.HandleFunc("GET /metrics/limiter", func(w http.ResponseWriter, _ *http.Request) {
+		writer.JSON(w, http.StatusOK, sharedLim.Snapshot())
+	})
+	mux.HandleFunc("GET /metrics/retries", func(w http.ResponseWriter, _ *http.Request) {
+		writer.JSON(w, http.StatusOK, retries.Snapshot())
+	})
+	mux.HandleFunc("GET /metrics/breakers", func(w http.ResponseWriter, _ *http.Request) {
+		writer.JSON(w, http.StatusOK, map[string]contracts.BreakerSnapshot{
+			"dispatcher":       dispatcherBreaker.Snapshot(),
+			"rate_limit_store": limitStoreBreaker.Snapshot(),
+		})
+	})
+	router := middleware.Chain(mux,
+		middleware.AdaptiveConcurrency(inFlight, sse_transport.IsStream),
+		middleware.PreferAsync("/transfer", asyncTransferHTTP(async, plugins)),
+	)
 
 	httpSrv, err := dt.NewHTTPServer(
 		":8080", router,
@@ -119,24 +416,61 @@ func main() {
 		log.Fatal(fmt.Errorf("http server init: %w", err))
 	}
 
+	// gRPC: the same transfer chain, async transfers and event stream, behind the
+	// same adaptive ingress limit as HTTP.
+	grpcSrv, err := grpc_transport.NewGRPCServer(":9090", func(gs *grpc.Server) {
+		pb.RegisterTransferServiceServer(gs, grpc_transport.NewTransferServer(plugins, h,
+			grpc_transport.WithAsyncTransfer(async.SubmitAsync, async.Status),
+			grpc_transport.WithEventStream(stream, admins),
+		))
+	}, grpc.UnaryInterceptor(grpc_transport.AdaptiveConcurrency(inFlight)))
+	if err != nil {
+		log.Fatal(fmt.Errorf("grpc server init: %w", err))
+	}
+
 	// Context + signals for graceful shutdown
 	httpCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	// Finish what the last shutdown left queued before taking new traffic.
+	if err := recovery.Resume(plugins.WithContext(httpCtx)); err != nil {
+		logger.Error(fmt.Errorf("resume queued transfers: %w", err))
+	}
+
 	// Start
 	go func() {
-		if err := httpSrv.Start(httpCtx); err != nil {
+		// Start returns ctx.Err() on a signal; the graceful shutdown below handles that.
+		if err := httpSrv.Start(httpCtx); err != nil && !errors.Is(err, context.Canceled) {
 			log.Fatal(fmt.Errorf("running server: %w", err)) // platform.Field{Key: "component", Value: "http"})
 			stop()
 		}
 	}()
+	go func() {
+		if err := grpcSrv.Start(httpCtx); err != nil && !errors.Is(err, context.Canceled) {
+			log.Fatal(fmt.Errorf("running grpc server: %w", err))
+		}
+	}()
 
 	<-httpCtx.Done()
 
 	// Shutdown
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 	defer cancel()
+	// Drain alongside the server, so requests waiting on queued jobs are answered.
+	drained := make(chan error, 1)
+	go func() { drained <- recovery.Persist(shutdownCtx) }()
+	grpcStopped := make(chan struct{})
+	go func() {
+		_ = grpcSrv.Shutdown(shutdownCtx)
+		close(grpcStopped)
+	}()
 	_ = httpSrv.Shutdown(shutdownCtx)
+	<-grpcStopped
+	if err := <-drained; err != nil {
+		logger.Error(fmt.Errorf("drain: %w", err))
+	}
+	// Drained transfers published their events; write the webhook state last.
+	hooks.Stop()
 
 	// // [policy.Plugins, inbound.IdempotentCommand, inbound.TransferResult]
 	//
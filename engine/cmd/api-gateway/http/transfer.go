@@ -0,0 +1,128 @@
+package main
+
+import (
+	"net/http"
+
+	"fintech-capstone/m/v2/internal/api_gateway/app/policy"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+	"fintech-capstone/m/v2/internal/platform/http_kit/writer"
+
+	"github.com/race-conditioned/hexa/fusion/dt"
+	"github.com/race-conditioned/hexa/horizon"
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// transferHTTP serves synchronous POST /transfer through dt like the other routes:
+// dt decodes the body, builds the RequestMeta, calls the gateway's "transfer"
+// handler and encodes the result. The route is bound with dt.Unary per request,
+// rather than through the fusion's route table, so the handler runs on the
+// request's context (a client that disconnects cancels its transfer), and
+// transferWriter can answer what dt cannot: dt maps only its own error codes
+// (everything else becomes 500).
+func transferHTTP(gw *horizon.Gateway[policy.Plugins], plugins policy.Plugins) http.HandlerFunc {
+	h, ok := gw.Handler("transfer")
+	if !ok {
+		panic("transfer handler not registered")
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		// The priority stage grants the asked class only within the client's tier.
+		ctx := outbound.WithRequestedPriority(r.Context(), r.Header.Get(inbound.PriorityClassHeader))
+		ctx, report := outbound.WithLimitReport(ctx)
+		body := &bodyReader{ReadCloser: r.Body}
+		r.Body = body
+		tw := &transferWriter{ResponseWriter: w, report: report, body: body}
+		payload := &transferPayload{header: r.Header.Get(inbound.IdempotencyKeyHeader)}
+		handler := func(ctx policy.Plugins, meta hexa_inbound.RequestMeta, cmd hexa_inbound.Command) (hexa_inbound.Result, error) {
+			tw.handled = true
+			if payload.err != nil {
+				tw.err = payload.err
+				return nil, tw.err
+			}
+			tw.res, tw.err = h(ctx, meta, cmd)
+			return tw.res, tw.err
+		}
+		newPayload := func() any { return payload }
+		dt.Unary(handler, newPayload, dt.DefaultMeta, plugins.WithContext(ctx))(tw, r)
+	}
+}
+
+// transferPayload is the body of POST /transfer. Its idempotency key may come
+// from the body or the Idempotency-Key header; if both are set they must match.
+type transferPayload struct {
+	inbound.TransferCommandHTTP
+	header string
+	err    error // set by ToCommand; the handler answers it instead of running
+}
+
+// ToCommand implements hexa_inbound.CommandDTO.
+func (p *transferPayload) ToCommand() hexa_inbound.Command {
+	key, err := inbound.ResolveIdempotencyKey(p.header, p.IdempotencyKey)
+	if err != nil {
+		p.err = apperr.Invalid(err.Error())
+	}
+	p.IdempotencyKey = key
+	return p.TransferCommandHTTP.ToCommand()
+}
+
+// transferWriter passes dt's response through, except where dt's encoding would
+// lose the outcome:
+//   - a failed transfer is answered by writeError (its apperr status, Retry-After,
+//     RateLimit-* and Idempotent-Replayed) instead of dt's 500;
+//   - a body dt could not decode, before the handler ran, is mapped by
+//     decodeError: a 400 only if it was read whole and is not valid JSON;
+//   - a transfer the dispatcher saved at shutdown answers 202 with its status URL;
+//     it resumes after restart (a replay of it too);
+//   - a replay answers 200 with Idempotent-Replayed: true (dt maps "duplicate" to 500);
+//   - an admitted transfer reports its rate-limit budget as RateLimit-* headers.
+type transferWriter struct {
+	http.ResponseWriter
+	report  *outbound.LimitReport
+	body    *bodyReader
+	handled bool
+	res     hexa_inbound.Result
+	err     error
+	dropped bool // dt's own error body is discarded
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *transferWriter) WriteHeader(status int) {
+	if d, ok := w.report.Decision(); ok {
+		writer.RateLimit(w.ResponseWriter, d.Limit, d.Remaining, d.Reset)
+	}
+	switch {
+	case !w.handled:
+		w.dropped = true
+		writeError(w.ResponseWriter, decodeError(w.body.err))
+		return
+	case w.err != nil:
+		w.dropped = true
+		writeError(w.ResponseWriter, w.err)
+		return
+	case accepted(w.res):
+		if inbound.Replayed(w.res) {
+			w.Header().Set(inbound.IdempotentReplayedHeader, "true")
+		}
+		w.Header().Set("Location", inbound.TransferStatusURL(w.res.(inbound.TransferResult).TransactionID()))
+		status = http.StatusAccepted
+	case inbound.Replayed(w.res):
+		w.Header().Set(inbound.IdempotentReplayedHeader, "true")
+		status = http.StatusOK
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// accepted reports whether res is a transfer saved for later completion.
+func accepted(res hexa_inbound.Result) bool {
+	tr, ok := res.(inbound.TransferResult)
+	return ok && tr.Accepted()
+}
+
+// Write implements http.ResponseWriter.
+func (w *transferWriter) Write(b []byte) (int, error) {
+	if w.dropped {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
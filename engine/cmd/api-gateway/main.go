@@ -4,7 +4,7 @@ package main
 import (
 	"context"
 	"errors"
-	grpc_api "fintech-capstone/m/v2/cmd/api-gateway/grpc/api"
+	"fintech-capstone/m/v2/cmd/api-gateway/gateway"
 	http_api "fintech-capstone/m/v2/cmd/api-gateway/http/api"
 	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
 	zap_adapter "fintech-capstone/m/v2/internal/platform/adapters/zap"
@@ -65,11 +65,12 @@ func main() {
 	defer func() { _ = zaplog.Sync() }()
 
 	logger := zap_adapter.New(zaplog)
-	httpSrv := http_api.BuildServer(logger)
-	grpcSrv := grpc_api.BuildServer(logger)
+	gw := gateway.BuildGateway(logger)
+	httpSrv := http_api.BuildServer(gw, logger)
 
-	// build gw, httpSrv, grpcSrv...
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
-	_ = runAll(ctx, httpSrv, grpcSrv)
+	if err := runAll(ctx, httpSrv); err != nil {
+		logger.Error(err)
+	}
 }
@@ -22,7 +22,9 @@ func BuildTransfer() (outbound.Limiter, outbound.Idempotency[hexa_inbound.Result
 type allowAllLimiter struct{}
 
 // Allow: always true
-func (a *allowAllLimiter) Allow(string) bool { return true }
+func (a *allowAllLimiter) Allow(string) outbound.LimitDecision {
+	return outbound.LimitDecision{Allowed: true}
+}
 
 // Idempotency: in-memory
 type inmemIdemp struct {
@@ -51,12 +53,20 @@ func (s *inmemIdemp) Store(k string, v hexa_inbound.Result) {
 // Dispatcher: immediately succeed
 type immediateDispatcher struct{}
 
-// Submit: immediately return success
-func (d *immediateDispatcher) Submit(_ context.Context, _ inbound.TransferCommand) inbound.TransferResult {
-	return inbound.NewTransferResult(uuid.New(), "success", "ok")
+// Submit: immediately return success, unless the caller already gave up
+func (d *immediateDispatcher) Submit(ctx context.Context, _ inbound.TransferCommand) (inbound.TransferResult, error) {
+	if err := ctx.Err(); err != nil {
+		return inbound.TransferResult{}, err
+	}
+	id, ok := outbound.TransactionIDFrom(ctx)
+	if !ok {
+		id = uuid.New()
+	}
+	return inbound.NewTransferResult(id, "success", "ok"), nil
 }
-func (d *immediateDispatcher) QueueDepth() int64    { return 0 }
-func (d *immediateDispatcher) ActiveWorkers() int64 { return 0 }
+func (d *immediateDispatcher) QueueDepth() int64                                   { return 0 }
+func (d *immediateDispatcher) QueueDepthByClass() map[outbound.PriorityClass]int64 { return nil }
+func (d *immediateDispatcher) ActiveWorkers() int64                                { return 0 }
 
 // Metrics: no-op
 type noopMetrics struct{}
@@ -70,5 +80,9 @@ func (*noopMetrics) IncSuccess()                         {}
 func (*noopMetrics) IncRateLimited()                     {}
 func (*noopMetrics) IncTimeout()                         {}
 func (*noopMetrics) IncIdempotentHit()                   {}
+func (*noopMetrics) IncDeadLettered()                    {}
+func (*noopMetrics) IncDeadLetterFailure()               {}
+func (*noopMetrics) IncEventPublishFailure()             {}
+func (*noopMetrics) IncCircuitRejected()                 {}
 func (*noopMetrics) ObserveLatency(time.Duration)        {}
 func (*noopMetrics) Snapshot() contracts.MetricsSnapshot { return contracts.MetricsSnapshot{} }
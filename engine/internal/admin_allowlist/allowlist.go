@@ -0,0 +1,29 @@
+package admin_allowlist
+
+import "fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+
+// Compile-time check that Allowlist implements outbound.Admins.
+var _ outbound.Admins = Allowlist{}
+
+// Allowlist is a fixed set of admin client IDs.
+// It is read-only after construction and safe for concurrent use.
+type Allowlist struct {
+	clients map[string]struct{}
+}
+
+// New creates an Allowlist of clientIDs. Empty IDs are ignored.
+func New(clientIDs ...string) Allowlist {
+	a := Allowlist{clients: make(map[string]struct{}, len(clientIDs))}
+	for _, id := range clientIDs {
+		if id != "" {
+			a.clients[id] = struct{}{}
+		}
+	}
+	return a
+}
+
+// IsAdmin implements outbound.Admins.
+func (a Allowlist) IsAdmin(clientID string) bool {
+	_, ok := a.clients[clientID]
+	return ok
+}
@@ -0,0 +1,8 @@
+// Package admin_allowlist provides an outbound.Admins backed by a fixed list of
+// client IDs.
+//
+// Design goals:
+//   - Admin rights are granted by name, explicitly, and by nothing else: not by a
+//     client's priority tier or plan.
+//   - Read-only after construction, so lookups need no locking.
+package admin_allowlist
@@ -0,0 +1,38 @@
+package grpc_transport
+
+import (
+	"context"
+
+	"fintech-capstone/m/v2/internal/platform/concurrency"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AdaptiveConcurrency is a unary interceptor admitting calls while the in-flight
+// count is under l's adaptive limit; others fail fast with Unavailable. Streaming
+// calls are long-lived and not limited. Unavailable and DeadlineExceeded results
+// count as drops; caller errors release their slot without a latency sample.
+func AdaptiveConcurrency(l *concurrency.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		tok, ok := l.Acquire()
+		if !ok {
+			return nil, status.Error(codes.Unavailable, "server busy")
+		}
+
+		outcome := concurrency.Ignored // if handler panics
+		defer func() { tok.Release(outcome) }()
+
+		resp, err := handler(ctx, req)
+		switch status.Code(err) {
+		case codes.Unavailable, codes.DeadlineExceeded:
+			outcome = concurrency.Dropped
+		case codes.InvalidArgument, codes.ResourceExhausted, codes.Canceled:
+			outcome = concurrency.Ignored
+		default:
+			outcome = concurrency.Success
+		}
+		return resp, err
+	}
+}
@@ -3,12 +3,14 @@ package grpc_transport
 import (
 	"context"
 	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
 
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
 	"google.golang.org/grpc/metadata"
 )
 
 // metaFromGRPC extracts request metadata from gRPC context.
-func metaFromGRPC(ctx context.Context, fullMethod string) inbound.RequestMeta {
+func metaFromGRPC(ctx context.Context, fullMethod string) hexa_inbound.RequestMeta {
 	first := func(vals []string) string {
 		if len(vals) > 0 {
 			return vals[0]
@@ -16,7 +18,7 @@ func metaFromGRPC(ctx context.Context, fullMethod string) inbound.RequestMeta {
 		return ""
 	}
 	md, _ := metadata.FromIncomingContext(ctx)
-	return inbound.RequestMeta{
+	return hexa_inbound.RequestMeta{
 		ClientID:  first(md.Get("x-client-id")),
 		RequestID: first(md.Get("x-request-id")),
 		TraceID:   first(md.Get("x-trace-id")),
@@ -24,3 +26,23 @@ func metaFromGRPC(ctx context.Context, fullMethod string) inbound.RequestMeta {
 		Target:    fullMethod,
 	}
 }
+
+// priorityFromGRPC returns ctx carrying the dispatcher priority class the client
+// asked for in the x-priority-class metadata key, if any. The priority stage grants
+// it only within the client's tier.
+func priorityFromGRPC(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if vals := md.Get(inbound.PriorityClassMetadata); len(vals) > 0 {
+		return outbound.WithRequestedPriority(ctx, vals[0])
+	}
+	return ctx
+}
+
+// idempotencyKeyFromGRPC returns the idempotency-key metadata value, if any.
+func idempotencyKeyFromGRPC(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if vals := md.Get(inbound.IdempotencyKeyMetadata); len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
@@ -27,6 +27,7 @@ type TransferCommand struct {
 	ToAccount      string                 `protobuf:"bytes,2,opt,name=to_account,json=toAccount,proto3" json:"to_account,omitempty"`
 	AmountCents    int64                  `protobuf:"varint,3,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
 	IdempotencyKey string                 `protobuf:"bytes,4,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	RespondAsync   bool                   `protobuf:"varint,5,opt,name=respond_async,json=respondAsync,proto3" json:"respond_async,omitempty"` // accept now, complete in the background; poll GetTransfer
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -89,6 +90,13 @@ func (x *TransferCommand) GetIdempotencyKey() string {
 	return ""
 }
 
+func (x *TransferCommand) GetRespondAsync() bool {
+	if x != nil {
+		return x.RespondAsync
+	}
+	return false
+}
+
 type TransferResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"` // use string, not uuid type
@@ -149,23 +157,456 @@ func (x *TransferResponse) GetMessage() string {
 	return ""
 }
 
+type GetTransferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTransferRequest) Reset() {
+	*x = GetTransferRequest{}
+	mi := &file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTransferRequest) ProtoMessage() {}
+
+func (x *GetTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTransferRequest.ProtoReflect.Descriptor instead.
+func (*GetTransferRequest) Descriptor() ([]byte, []int) {
+	return file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetTransferRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+type TransferStatus struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId     string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	State             string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`   // pending, completed or failed
+	Status            string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // transfer result status once completed
+	Message           string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	Error             string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"` // set when failed
+	AcceptedAtUnixMs  int64                  `protobuf:"varint,6,opt,name=accepted_at_unix_ms,json=acceptedAtUnixMs,proto3" json:"accepted_at_unix_ms,omitempty"`
+	CompletedAtUnixMs int64                  `protobuf:"varint,7,opt,name=completed_at_unix_ms,json=completedAtUnixMs,proto3" json:"completed_at_unix_ms,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *TransferStatus) Reset() {
+	*x = TransferStatus{}
+	mi := &file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferStatus) ProtoMessage() {}
+
+func (x *TransferStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferStatus.ProtoReflect.Descriptor instead.
+func (*TransferStatus) Descriptor() ([]byte, []int) {
+	return file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TransferStatus) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *TransferStatus) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *TransferStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TransferStatus) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TransferStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *TransferStatus) GetAcceptedAtUnixMs() int64 {
+	if x != nil {
+		return x.AcceptedAtUnixMs
+	}
+	return 0
+}
+
+func (x *TransferStatus) GetCompletedAtUnixMs() int64 {
+	if x != nil {
+		return x.CompletedAtUnixMs
+	}
+	return 0
+}
+
+type WatchTransfersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Account       string                 `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`                              // source or destination account; empty = all
+	ClientId      string                 `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`            // empty = all
+	LastEventId   string                 `protobuf:"bytes,3,opt,name=last_event_id,json=lastEventId,proto3" json:"last_event_id,omitempty"` // resume after this event
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchTransfersRequest) Reset() {
+	*x = WatchTransfersRequest{}
+	mi := &file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTransfersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTransfersRequest) ProtoMessage() {}
+
+func (x *WatchTransfersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTransfersRequest.ProtoReflect.Descriptor instead.
+func (*WatchTransfersRequest) Descriptor() ([]byte, []int) {
+	return file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *WatchTransfersRequest) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+func (x *WatchTransfersRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *WatchTransfersRequest) GetLastEventId() string {
+	if x != nil {
+		return x.LastEventId
+	}
+	return ""
+}
+
+type TransferEvent struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type             string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // transfer.settled or transfer.rejected
+	ClientId         string                 `protobuf:"bytes,3,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	TransactionId    string                 `protobuf:"bytes,4,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	FromAccount      string                 `protobuf:"bytes,5,opt,name=from_account,json=fromAccount,proto3" json:"from_account,omitempty"`
+	ToAccount        string                 `protobuf:"bytes,6,opt,name=to_account,json=toAccount,proto3" json:"to_account,omitempty"`
+	AmountCents      int64                  `protobuf:"varint,7,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
+	IdempotencyKey   string                 `protobuf:"bytes,8,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	Status           string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`
+	Message          string                 `protobuf:"bytes,10,opt,name=message,proto3" json:"message,omitempty"`
+	OccurredAtUnixMs int64                  `protobuf:"varint,11,opt,name=occurred_at_unix_ms,json=occurredAtUnixMs,proto3" json:"occurred_at_unix_ms,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TransferEvent) Reset() {
+	*x = TransferEvent{}
+	mi := &file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferEvent) ProtoMessage() {}
+
+func (x *TransferEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferEvent.ProtoReflect.Descriptor instead.
+func (*TransferEvent) Descriptor() ([]byte, []int) {
+	return file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TransferEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TransferEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *TransferEvent) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *TransferEvent) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *TransferEvent) GetFromAccount() string {
+	if x != nil {
+		return x.FromAccount
+	}
+	return ""
+}
+
+func (x *TransferEvent) GetToAccount() string {
+	if x != nil {
+		return x.ToAccount
+	}
+	return ""
+}
+
+func (x *TransferEvent) GetAmountCents() int64 {
+	if x != nil {
+		return x.AmountCents
+	}
+	return 0
+}
+
+func (x *TransferEvent) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *TransferEvent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TransferEvent) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TransferEvent) GetOccurredAtUnixMs() int64 {
+	if x != nil {
+		return x.OccurredAtUnixMs
+	}
+	return 0
+}
+
+type TransferStreamEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`            // stream position; pass as last_event_id to resume
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`        // event, lag or reset
+	Event         *TransferEvent         `protobuf:"bytes,3,opt,name=event,proto3" json:"event,omitempty"`      // set when kind = event
+	Dropped       uint64                 `protobuf:"varint,4,opt,name=dropped,proto3" json:"dropped,omitempty"` // set when kind = lag: events dropped because the watcher fell behind
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`    // set when kind = reset
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferStreamEvent) Reset() {
+	*x = TransferStreamEvent{}
+	mi := &file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferStreamEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferStreamEvent) ProtoMessage() {}
+
+func (x *TransferStreamEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferStreamEvent.ProtoReflect.Descriptor instead.
+func (*TransferStreamEvent) Descriptor() ([]byte, []int) {
+	return file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TransferStreamEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TransferStreamEvent) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *TransferStreamEvent) GetEvent() *TransferEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *TransferStreamEvent) GetDropped() uint64 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
+
+func (x *TransferStreamEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
 var File_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto protoreflect.FileDescriptor
 
 const file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_rawDesc = "" +
 	"\n" +
-	"?internal/api_gateway/adapters/inbound/grpc/proto/transfer.proto\x12\vtransfer.v1\"\x9f\x01\n" +
+	"?internal/api_gateway/adapters/inbound/grpc/proto/transfer.proto\x12\vtransfer.v1\"\xc4\x01\n" +
 	"\x0fTransferCommand\x12!\n" +
 	"\ffrom_account\x18\x01 \x01(\tR\vfromAccount\x12\x1d\n" +
 	"\n" +
 	"to_account\x18\x02 \x01(\tR\ttoAccount\x12!\n" +
 	"\famount_cents\x18\x03 \x01(\x03R\vamountCents\x12'\n" +
-	"\x0fidempotency_key\x18\x04 \x01(\tR\x0eidempotencyKey\"k\n" +
+	"\x0fidempotency_key\x18\x04 \x01(\tR\x0eidempotencyKey\x12#\n" +
+	"\rrespond_async\x18\x05 \x01(\bR\frespondAsync\"k\n" +
 	"\x10TransferResponse\x12%\n" +
 	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\x12\x18\n" +
-	"\amessage\x18\x03 \x01(\tR\amessage2Z\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\";\n" +
+	"\x12GetTransferRequest\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\"\xf5\x01\n" +
+	"\x0eTransferStatus\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12-\n" +
+	"\x13accepted_at_unix_ms\x18\x06 \x01(\x03R\x10acceptedAtUnixMs\x12/\n" +
+	"\x14completed_at_unix_ms\x18\a \x01(\x03R\x11completedAtUnixMs\"r\n" +
+	"\x15WatchTransfersRequest\x12\x18\n" +
+	"\aaccount\x18\x01 \x01(\tR\aaccount\x12\x1b\n" +
+	"\tclient_id\x18\x02 \x01(\tR\bclientId\x12\"\n" +
+	"\rlast_event_id\x18\x03 \x01(\tR\vlastEventId\"\xe6\x02\n" +
+	"\rTransferEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1b\n" +
+	"\tclient_id\x18\x03 \x01(\tR\bclientId\x12%\n" +
+	"\x0etransaction_id\x18\x04 \x01(\tR\rtransactionId\x12!\n" +
+	"\ffrom_account\x18\x05 \x01(\tR\vfromAccount\x12\x1d\n" +
+	"\n" +
+	"to_account\x18\x06 \x01(\tR\ttoAccount\x12!\n" +
+	"\famount_cents\x18\a \x01(\x03R\vamountCents\x12'\n" +
+	"\x0fidempotency_key\x18\b \x01(\tR\x0eidempotencyKey\x12\x16\n" +
+	"\x06status\x18\t \x01(\tR\x06status\x12\x18\n" +
+	"\amessage\x18\n" +
+	" \x01(\tR\amessage\x12-\n" +
+	"\x13occurred_at_unix_ms\x18\v \x01(\x03R\x10occurredAtUnixMs\"\x9d\x01\n" +
+	"\x13TransferStreamEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x120\n" +
+	"\x05event\x18\x03 \x01(\v2\x1a.transfer.v1.TransferEventR\x05event\x12\x18\n" +
+	"\adropped\x18\x04 \x01(\x04R\adropped\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason2\x81\x02\n" +
 	"\x0fTransferService\x12G\n" +
-	"\bTransfer\x12\x1c.transfer.v1.TransferCommand\x1a\x1d.transfer.v1.TransferResponseBNZLfintech-capstone/m/v2/internal/api_gateway/adapters/inbound/grpc/proto;protob\x06proto3"
+	"\bTransfer\x12\x1c.transfer.v1.TransferCommand\x1a\x1d.transfer.v1.TransferResponse\x12K\n" +
+	"\vGetTransfer\x12\x1f.transfer.v1.GetTransferRequest\x1a\x1b.transfer.v1.TransferStatus\x12X\n" +
+	"\x0eWatchTransfers\x12\".transfer.v1.WatchTransfersRequest\x1a .transfer.v1.TransferStreamEvent0\x01BNZLfintech-capstone/m/v2/internal/api_gateway/adapters/inbound/grpc/proto;protob\x06proto3"
 
 var (
 	file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_rawDescOnce sync.Once
@@ -179,19 +620,29 @@ func file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_rawDes
 	return file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_rawDescData
 }
 
-var file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_goTypes = []any{
-	(*TransferCommand)(nil),  // 0: transfer.v1.TransferCommand
-	(*TransferResponse)(nil), // 1: transfer.v1.TransferResponse
+	(*TransferCommand)(nil),       // 0: transfer.v1.TransferCommand
+	(*TransferResponse)(nil),      // 1: transfer.v1.TransferResponse
+	(*GetTransferRequest)(nil),    // 2: transfer.v1.GetTransferRequest
+	(*TransferStatus)(nil),        // 3: transfer.v1.TransferStatus
+	(*WatchTransfersRequest)(nil), // 4: transfer.v1.WatchTransfersRequest
+	(*TransferEvent)(nil),         // 5: transfer.v1.TransferEvent
+	(*TransferStreamEvent)(nil),   // 6: transfer.v1.TransferStreamEvent
 }
 var file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_depIdxs = []int32{
-	0, // 0: transfer.v1.TransferService.Transfer:input_type -> transfer.v1.TransferCommand
-	1, // 1: transfer.v1.TransferService.Transfer:output_type -> transfer.v1.TransferResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	5, // 0: transfer.v1.TransferStreamEvent.event:type_name -> transfer.v1.TransferEvent
+	0, // 1: transfer.v1.TransferService.Transfer:input_type -> transfer.v1.TransferCommand
+	2, // 2: transfer.v1.TransferService.GetTransfer:input_type -> transfer.v1.GetTransferRequest
+	4, // 3: transfer.v1.TransferService.WatchTransfers:input_type -> transfer.v1.WatchTransfersRequest
+	1, // 4: transfer.v1.TransferService.Transfer:output_type -> transfer.v1.TransferResponse
+	3, // 5: transfer.v1.TransferService.GetTransfer:output_type -> transfer.v1.TransferStatus
+	6, // 6: transfer.v1.TransferService.WatchTransfers:output_type -> transfer.v1.TransferStreamEvent
+	4, // [4:7] is the sub-list for method output_type
+	1, // [1:4] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_init() }
@@ -205,7 +656,7 @@ func file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_init()
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_rawDesc), len(file_internal_api_gateway_adapters_inbound_grpc_proto_transfer_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,7 +19,9 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	TransferService_Transfer_FullMethodName = "/transfer.v1.TransferService/Transfer"
+	TransferService_Transfer_FullMethodName       = "/transfer.v1.TransferService/Transfer"
+	TransferService_GetTransfer_FullMethodName    = "/transfer.v1.TransferService/GetTransfer"
+	TransferService_WatchTransfers_FullMethodName = "/transfer.v1.TransferService/WatchTransfers"
 )
 
 // TransferServiceClient is the client API for TransferService service.
@@ -27,6 +29,8 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type TransferServiceClient interface {
 	Transfer(ctx context.Context, in *TransferCommand, opts ...grpc.CallOption) (*TransferResponse, error)
+	GetTransfer(ctx context.Context, in *GetTransferRequest, opts ...grpc.CallOption) (*TransferStatus, error)
+	WatchTransfers(ctx context.Context, in *WatchTransfersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TransferStreamEvent], error)
 }
 
 type transferServiceClient struct {
@@ -47,11 +51,42 @@ func (c *transferServiceClient) Transfer(ctx context.Context, in *TransferComman
 	return out, nil
 }
 
+func (c *transferServiceClient) GetTransfer(ctx context.Context, in *GetTransferRequest, opts ...grpc.CallOption) (*TransferStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferStatus)
+	err := c.cc.Invoke(ctx, TransferService_GetTransfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transferServiceClient) WatchTransfers(ctx context.Context, in *WatchTransfersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TransferStreamEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TransferService_ServiceDesc.Streams[0], TransferService_WatchTransfers_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchTransfersRequest, TransferStreamEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TransferService_WatchTransfersClient = grpc.ServerStreamingClient[TransferStreamEvent]
+
 // TransferServiceServer is the server API for TransferService service.
 // All implementations must embed UnimplementedTransferServiceServer
 // for forward compatibility.
 type TransferServiceServer interface {
 	Transfer(context.Context, *TransferCommand) (*TransferResponse, error)
+	GetTransfer(context.Context, *GetTransferRequest) (*TransferStatus, error)
+	WatchTransfers(*WatchTransfersRequest, grpc.ServerStreamingServer[TransferStreamEvent]) error
 	mustEmbedUnimplementedTransferServiceServer()
 }
 
@@ -65,6 +100,12 @@ type UnimplementedTransferServiceServer struct{}
 func (UnimplementedTransferServiceServer) Transfer(context.Context, *TransferCommand) (*TransferResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Transfer not implemented")
 }
+func (UnimplementedTransferServiceServer) GetTransfer(context.Context, *GetTransferRequest) (*TransferStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransfer not implemented")
+}
+func (UnimplementedTransferServiceServer) WatchTransfers(*WatchTransfersRequest, grpc.ServerStreamingServer[TransferStreamEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchTransfers not implemented")
+}
 func (UnimplementedTransferServiceServer) mustEmbedUnimplementedTransferServiceServer() {}
 func (UnimplementedTransferServiceServer) testEmbeddedByValue()                         {}
 
@@ -104,6 +145,35 @@ func _TransferService_Transfer_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TransferService_GetTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransferServiceServer).GetTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransferService_GetTransfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransferServiceServer).GetTransfer(ctx, req.(*GetTransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransferService_WatchTransfers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTransfersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TransferServiceServer).WatchTransfers(m, &grpc.GenericServerStream[WatchTransfersRequest, TransferStreamEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TransferService_WatchTransfersServer = grpc.ServerStreamingServer[TransferStreamEvent]
+
 // TransferService_ServiceDesc is the grpc.ServiceDesc for TransferService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,7 +185,17 @@ var TransferService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Transfer",
 			Handler:    _TransferService_Transfer_Handler,
 		},
+		{
+			MethodName: "GetTransfer",
+			Handler:    _TransferService_GetTransfer_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchTransfers",
+			Handler:       _TransferService_WatchTransfers_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "internal/api_gateway/adapters/inbound/grpc/proto/transfer.proto",
 }
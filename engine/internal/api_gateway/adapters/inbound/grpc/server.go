@@ -14,13 +14,14 @@ type GRPCServer struct {
 }
 
 // NewGRPCServer creates a new GRPCServer listening on the given address.
-func NewGRPCServer(addr string, register func(*grpc.Server)) (*GRPCServer, error) {
+// opts carry interceptors such as AdaptiveConcurrency.
+func NewGRPCServer(addr string, register func(*grpc.Server), opts ...grpc.ServerOption) (*GRPCServer, error) {
 	ln, err := net.Listen("tcp", addr)
 	if err != nil {
 		return nil, err
 	}
-	gs := grpc.NewServer() // add interceptors later
-	register(gs)           // register generated services here
+	gs := grpc.NewServer(opts...)
+	register(gs) // register generated services here
 	return &GRPCServer{srv: gs, ln: ln}, nil
 }
 
@@ -3,36 +3,103 @@ package grpc_transport
 import (
 	"context"
 	pb "fintech-capstone/m/v2/internal/api_gateway/adapters/inbound/grpc/proto"
-	"fintech-capstone/m/v2/internal/api_gateway/entrypoint"
+	"fintech-capstone/m/v2/internal/api_gateway/app/policy"
 	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 // TransferServer is the gRPC server for transfer operations.
 type TransferServer struct {
 	pb.UnimplementedTransferServiceServer
-	h inbound.UnaryHandler[inbound.TransferCommand, inbound.TransferResult]
+	plugins policy.Plugins
+	h       policy.TransferHandler
+	async   policy.TransferHandler
+	status  StatusHandler
+	stream  outbound.EventStream
+	admins  outbound.Admins
+}
+
+// StatusHandler answers a poll for an asynchronously submitted transfer.
+type StatusHandler func(policy.Plugins, hexa_inbound.RequestMeta, inbound.TransferStatusCommand) (inbound.TransferStatusResult, error)
+
+// TransferServerOption configures optional TransferServer capabilities.
+type TransferServerOption func(*TransferServer)
+
+// WithAsyncTransfer serves respond_async transfers with submit and GetTransfer
+// with status. Without it both are answered Unimplemented.
+func WithAsyncTransfer(submit policy.TransferHandler, status StatusHandler) TransferServerOption {
+	return func(s *TransferServer) {
+		s.async = submit
+		s.status = status
+	}
 }
 
-// NewTransferServer creates a new TransferServer.
-func NewTransferServer(gw *entrypoint.Gateway) *TransferServer {
-	return &TransferServer{h: gw.TransferHandler}
+// WithEventStream serves WatchTransfers from stream; admins may watch other
+// clients (see outbound.StreamFilter.ScopeTo). Without it WatchTransfers is
+// answered Unimplemented.
+func WithEventStream(stream outbound.EventStream, admins outbound.Admins) TransferServerOption {
+	return func(s *TransferServer) {
+		s.stream = stream
+		s.admins = admins
+	}
+}
+
+// NewTransferServer creates a new TransferServer. Calls run transfer (the composed
+// transfer handler) on plugins bound to the call's context, so a client that goes
+// away cancels its transfer.
+func NewTransferServer(plugins policy.Plugins, transfer policy.TransferHandler, opts ...TransferServerOption) *TransferServer {
+	s := &TransferServer{
+		plugins: plugins,
+		h:       transfer,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-// Transfer handles transfer requests.
+// Transfer handles transfer requests. The idempotency key may also come from the
+// idempotency-key metadata; replays set the idempotent-replayed trailer.
+// With respond_async set, it returns as soon as the transfer is accepted (status
+// "accepted"); the outcome is polled with GetTransfer.
 func (s *TransferServer) Transfer(ctx context.Context, req *pb.TransferCommand) (*pb.TransferResponse, error) {
 	meta := metaFromGRPC(ctx, pb.TransferService_Transfer_FullMethodName)
 
+	key, err := inbound.ResolveIdempotencyKey(idempotencyKeyFromGRPC(ctx), req.GetIdempotencyKey())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	cmd := inbound.NewTransferCommand(
 		req.GetFromAccount(),
 		req.GetToAccount(),
 		req.GetAmountCents(),
-		req.GetIdempotencyKey(),
+		key,
 	)
 
-	res, err := s.h(ctx, meta, cmd)
+	h := s.h
+	if req.GetRespondAsync() {
+		if s.async == nil {
+			return nil, status.Error(codes.Unimplemented, "asynchronous transfers are not enabled")
+		}
+		h = s.async
+	}
+	res, err := h(s.plugins.WithContext(priorityFromGRPC(ctx)), meta, cmd)
 	if err != nil {
+		if apperr.As(err).Replayed {
+			_ = grpc.SetTrailer(ctx, metadata.Pairs(inbound.IdempotentReplayedTrailer, "true"))
+		}
 		return nil, toGRPCError(err)
 	}
+	if inbound.Replayed(res) {
+		_ = grpc.SetTrailer(ctx, metadata.Pairs(inbound.IdempotentReplayedTrailer, "true"))
+	}
 
 	return &pb.TransferResponse{
 		TransactionId: res.TransactionID().String(),
@@ -40,3 +107,102 @@ func (s *TransferServer) Transfer(ctx context.Context, req *pb.TransferCommand)
 		Message:       res.Message(),
 	}, nil
 }
+
+// GetTransfer reports the state of an asynchronously submitted transfer to the
+// client that submitted it.
+func (s *TransferServer) GetTransfer(ctx context.Context, req *pb.GetTransferRequest) (*pb.TransferStatus, error) {
+	if s.status == nil {
+		return nil, status.Error(codes.Unimplemented, "asynchronous transfers are not enabled")
+	}
+	meta := metaFromGRPC(ctx, pb.TransferService_GetTransfer_FullMethodName)
+
+	res, err := s.status(s.plugins.WithContext(ctx), meta, inbound.NewTransferStatusCommand(req.GetTransactionId()))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	st := res.Response()
+	out := &pb.TransferStatus{
+		TransactionId:    st.TransactionID,
+		State:            st.State,
+		Status:           st.Status,
+		Message:          st.Message,
+		Error:            st.Error,
+		AcceptedAtUnixMs: st.AcceptedAt.UnixMilli(),
+	}
+	if st.CompletedAt != nil {
+		out.CompletedAtUnixMs = st.CompletedAt.UnixMilli()
+	}
+	return out, nil
+}
+
+// WatchTransfers streams transfer events matching the request's filter until the
+// client goes away. client_id must be the caller's x-client-id unless the caller
+// is internal. Resume with last_event_id; a "lag" message reports events
+// dropped because the client read too slowly, a "reset" message a lost resume point.
+func (s *TransferServer) WatchTransfers(req *pb.WatchTransfersRequest, srv grpc.ServerStreamingServer[pb.TransferStreamEvent]) error {
+	if s.stream == nil {
+		return status.Error(codes.Unimplemented, "transfer streaming is not enabled")
+	}
+	meta := metaFromGRPC(srv.Context(), pb.TransferService_WatchTransfers_FullMethodName)
+	filter := outbound.StreamFilter{
+		Account:  req.GetAccount(),
+		ClientID: req.GetClientId(),
+	}
+	if err := filter.ScopeTo(meta.ClientID, s.admins); err != nil {
+		return toGRPCError(err)
+	}
+	sub, err := s.stream.Watch(filter, req.GetLastEventId())
+	if err != nil {
+		return toGRPCError(err)
+	}
+	defer sub.Close()
+
+	replay, gap := sub.Replay()
+	if gap && req.GetLastEventId() != "" {
+		if err := srv.Send(&pb.TransferStreamEvent{Kind: "reset", Reason: "resume point no longer buffered"}); err != nil {
+			return err
+		}
+	}
+	for _, se := range replay {
+		if err := srv.Send(toPBStreamEvent(se)); err != nil {
+			return err
+		}
+	}
+
+	for {
+		var msg *pb.TransferStreamEvent
+		select {
+		case <-srv.Context().Done():
+			return nil
+		case se := <-sub.Events():
+			msg = toPBStreamEvent(se)
+		case <-sub.Lagged():
+			msg = &pb.TransferStreamEvent{Kind: "lag", Dropped: sub.TakeDropped()}
+		}
+		if err := srv.Send(msg); err != nil {
+			return err
+		}
+	}
+}
+
+func toPBStreamEvent(se outbound.StreamEvent) *pb.TransferStreamEvent {
+	ev := se.Event
+	return &pb.TransferStreamEvent{
+		Id:   se.ID,
+		Kind: "event",
+		Event: &pb.TransferEvent{
+			Id:               ev.ID,
+			Type:             ev.Type.String(),
+			ClientId:         ev.ClientID,
+			TransactionId:    ev.TransactionID,
+			FromAccount:      ev.FromAccount,
+			ToAccount:        ev.ToAccount,
+			AmountCents:      ev.AmountCents,
+			IdempotencyKey:   ev.IdempotencyKey,
+			Status:           ev.Status,
+			Message:          ev.Message,
+			OccurredAtUnixMs: ev.OccurredAt.UnixMilli(),
+		},
+	}
+}
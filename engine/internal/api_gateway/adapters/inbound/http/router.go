@@ -3,6 +3,7 @@ package http_transport
 import (
 	"encoding/json"
 	"errors"
+	sse_transport "fintech-capstone/m/v2/internal/api_gateway/adapters/inbound/sse"
 	"fintech-capstone/m/v2/internal/api_gateway/contracts"
 	"fintech-capstone/m/v2/internal/api_gateway/entrypoint"
 	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
@@ -24,6 +25,9 @@ func NewRouter(gw *entrypoint.Gateway, logger platform.Logger) http.Handler {
 			gw.TransferHandler,
 			TransferJSONDecoder(),
 			func(w http.ResponseWriter, res inbound.TransferResult) {
+				if inbound.Replayed(res) {
+					w.Header().Set(inbound.IdempotentReplayedHeader, "true")
+				}
 				writer.JSON(w, http.StatusOK, contracts.TransferResponse{
 					TransactionID: res.TransactionID().String(),
 					Status:        res.Status().String(),
@@ -61,18 +65,26 @@ func NewRouter(gw *entrypoint.Gateway, logger platform.Logger) http.Handler {
 
 	lightLimiter := limiter.NewLightLimiter(10, 20)
 
+	// The adaptive limit replaces a fixed MaxInFlight; keep the static cap only
+	// when no limiter was configured.
+	inFlight := middleware.MaxInFlight(1024)
+	if l := gw.ConcurrencyLimiter(); l != nil {
+		inFlight = middleware.AdaptiveConcurrency(l, sse_transport.IsStream)
+	}
+
 	return middleware.Chain(
 		mux,
 		middleware.RecovererWithLogger(logger),
 		middleware.RequestID,
 		middleware.RequestLogger(logger),
 		middleware.RateLimitHTTP(lightLimiter.Allow),
-		middleware.MaxInFlight(1024),
+		inFlight,
 		middleware.LimitBytes(1<<20),
 	)
 }
 
-// TransferJSONDecoder decodes a TransferCommand from a JSON HTTP request.
+// TransferJSONDecoder decodes a TransferCommand from a JSON HTTP request. The
+// idempotency key may come from the body or the Idempotency-Key header.
 func TransferJSONDecoder() Decoder[inbound.TransferCommand] {
 	return func(r *http.Request) (inbound.TransferCommand, error) {
 		var dto struct {
@@ -90,11 +102,15 @@ func TransferJSONDecoder() Decoder[inbound.TransferCommand] {
 			}
 			return inbound.TransferCommand{}, apperr.Invalid("invalid JSON payload")
 		}
+		key, err := inbound.ResolveIdempotencyKey(r.Header.Get(inbound.IdempotencyKeyHeader), dto.IdempotencyKey)
+		if err != nil {
+			return inbound.TransferCommand{}, apperr.Invalid(err.Error())
+		}
 		return inbound.NewTransferCommand(
 			dto.From,
 			dto.To,
 			dto.Amount,
-			dto.IdempotencyKey,
+			key,
 		), nil
 	}
 }
@@ -1,7 +1,10 @@
 package http_transport
 
 import (
+	"context"
+	"errors"
 	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
 	"fintech-capstone/m/v2/internal/platform/apperr"
 	"fintech-capstone/m/v2/internal/platform/http_kit/middleware"
 	"fintech-capstone/m/v2/internal/platform/http_kit/writer"
@@ -19,6 +22,14 @@ type (
 // encodeError encodes an error into an HTTP response.
 func encodeError(w http.ResponseWriter, err error) {
 	e := apperr.As(err)
+	writer.RetryAfter(w, e.RetryAfter)
+	var limit outbound.LimitExceeded
+	if errors.As(e.Err, &limit) {
+		writer.RateLimit(w, limit.Limit, limit.Remaining, limit.Reset)
+	}
+	if e.Replayed {
+		w.Header().Set(inbound.IdempotentReplayedHeader, "true")
+	}
 	switch e.Code {
 	case apperr.CodeInvalid:
 		writer.JSON(w, http.StatusBadRequest, map[string]string{"error": e.Msg})
@@ -28,10 +39,12 @@ func encodeError(w http.ResponseWriter, err error) {
 		writer.JSON(w, http.StatusGatewayTimeout, map[string]string{"error": e.Msg})
 	case apperr.CodeNotFound:
 		writer.JSON(w, http.StatusNotFound, map[string]string{"error": e.Msg})
-	case apperr.CodeConflict:
+	case apperr.CodeConflict, apperr.CodeInProgress:
 		writer.JSON(w, http.StatusConflict, map[string]string{"error": e.Msg})
 	case apperr.CodePayloadTooLarge:
 		writer.JSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": e.Msg})
+	case apperr.CodeOverloaded:
+		writer.JSON(w, http.StatusServiceUnavailable, map[string]string{"error": e.Msg})
 	default:
 		writer.JSON(w, http.StatusInternalServerError, map[string]string{"error": e.Msg})
 	}
@@ -61,11 +74,15 @@ func Unary[Req any, Res any](
 			return
 		}
 		meta := metaFrom(r)
-		res, err := h(r.Context(), meta, req)
+		ctx, report := outbound.WithLimitReport(PriorityContext(r))
+		res, err := h(ctx, meta, req)
 		if err != nil {
 			encodeError(w, err)
 			return
 		}
+		if d, ok := report.Decision(); ok {
+			writer.RateLimit(w, d.Limit, d.Remaining, d.Reset)
+		}
 		enc(w, res)
 	}
 }
@@ -83,6 +100,13 @@ func DefaultMeta(r *http.Request) inbound.RequestMeta {
 	}
 }
 
+// PriorityContext returns the request context, carrying the dispatcher priority
+// class the client asked for in the X-Priority-Class header, if any. The priority
+// stage grants it only within the client's tier.
+func PriorityContext(r *http.Request) context.Context {
+	return outbound.WithRequestedPriority(r.Context(), r.Header.Get(inbound.PriorityClassHeader))
+}
+
 // firstNonEmpty returns the first non-empty string from the provided values.
 func firstNonEmpty(vals ...string) string {
 	for _, v := range vals {
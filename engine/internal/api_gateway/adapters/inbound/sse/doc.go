@@ -0,0 +1,3 @@
+// Package sse_transport serves the live transfer event stream as Server-Sent Events.
+// It is plain net/http so both the legacy router and the dt-based gateway can mount it.
+package sse_transport
@@ -0,0 +1,136 @@
+package sse_transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+	"fintech-capstone/m/v2/internal/platform/http_kit/writer"
+)
+
+// StreamPath is where TransferStream is mounted.
+const StreamPath = "/transfers/stream"
+
+// IsStream reports whether r is a transfer stream request, e.g. to exempt it from
+// limits meant for short requests.
+func IsStream(r *http.Request) bool {
+	return r.URL.Path == StreamPath
+}
+
+// Event names written besides the transfer event types themselves.
+const (
+	EventLag   = "lag"   // the watcher fell behind and events were dropped
+	EventReset = "reset" // the resume point is no longer buffered; refetch state
+)
+
+// LagNotice is the data of a lag event.
+type LagNotice struct {
+	Dropped uint64 `json:"dropped"`
+}
+
+// ResetNotice is the data of a reset event.
+type ResetNotice struct {
+	Reason string `json:"reason"`
+}
+
+// TransferStream serves GET /transfers/stream. Query parameters `account` and
+// `client_id` filter the stream; `Last-Event-ID` (or `last_event_id`) resumes it.
+// `client_id` must be the caller's X-Client-ID unless the caller is one of admins
+// (see outbound.StreamFilter.ScopeTo).
+// A comment line is written every heartbeat (<= 0 => 15s) to keep proxies from
+// closing idle connections.
+func TransferStream(stream outbound.EventStream, admins outbound.Admins, heartbeat time.Duration) http.HandlerFunc {
+	if heartbeat <= 0 {
+		heartbeat = 15 * time.Second
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter := outbound.StreamFilter{
+			Account:  r.URL.Query().Get("account"),
+			ClientID: r.URL.Query().Get("client_id"),
+		}
+		if err := filter.ScopeTo(r.Header.Get("X-Client-ID"), admins); err != nil {
+			writer.Error(w, http.StatusBadRequest, apperr.As(err).Msg)
+			return
+		}
+		lastID := r.Header.Get("Last-Event-ID")
+		if lastID == "" {
+			lastID = r.URL.Query().Get("last_event_id")
+		}
+
+		sub, err := stream.Watch(filter, lastID)
+		if err != nil {
+			if apperr.As(err).Code == apperr.CodeOverloaded {
+				writer.Error(w, http.StatusServiceUnavailable, apperr.As(err).Msg)
+				return
+			}
+			writer.Error(w, http.StatusInternalServerError, apperr.As(err).Msg)
+			return
+		}
+		defer sub.Close()
+
+		// Streams outlive the server's WriteTimeout, so lift it for this response.
+		rc := http.NewResponseController(w)
+		_ = rc.SetWriteDeadline(time.Time{})
+
+		h := w.Header()
+		h.Set("Content-Type", "text/event-stream")
+		h.Set("Cache-Control", "no-cache")
+		h.Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		replay, gap := sub.Replay()
+		if gap && lastID != "" {
+			if writeEvent(w, "", EventReset, ResetNotice{Reason: "resume point no longer buffered"}) != nil {
+				return
+			}
+		}
+		for _, se := range replay {
+			if writeEvent(w, se.ID, se.Event.Type.String(), se.Event) != nil {
+				return
+			}
+		}
+		if rc.Flush() != nil {
+			return
+		}
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		for {
+			var err error
+			select {
+			case <-r.Context().Done():
+				return
+			case se := <-sub.Events():
+				err = writeEvent(w, se.ID, se.Event.Type.String(), se.Event)
+			case <-sub.Lagged():
+				err = writeEvent(w, "", EventLag, LagNotice{Dropped: sub.TakeDropped()})
+			case <-ticker.C:
+				_, err = fmt.Fprint(w, ": ping\n\n")
+			}
+			if err == nil {
+				err = rc.Flush()
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeEvent writes one SSE message. An empty id leaves the client's last ID untouched.
+func writeEvent(w http.ResponseWriter, id, event string, data any) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if id != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, b)
+	return err
+}
@@ -0,0 +1,45 @@
+package sse_transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"fintech-capstone/m/v2/internal/admin_allowlist"
+	"fintech-capstone/m/v2/internal/event_stream"
+)
+
+func TestTransferStreamIsScopedToTheCaller(t *testing.T) {
+	admins := admin_allowlist.New("ledger-sweeper")
+	srv := httptest.NewServer(TransferStream(event_stream.New(event_stream.Config{}), admins, time.Hour))
+	t.Cleanup(srv.Close)
+
+	for name, tc := range map[string]struct {
+		caller, filter string
+		want           int
+	}{
+		"own events":        {"alice", "alice", http.StatusOK},
+		"anonymous":         {"", "alice", http.StatusBadRequest},
+		"unscoped":          {"alice", "", http.StatusBadRequest},
+		"another client":    {"alice", "bob", http.StatusBadRequest},
+		"admin, any client": {"ledger-sweeper", "bob", http.StatusOK},
+		"admin, unscoped":   {"ledger-sweeper", "", http.StatusOK},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL+StreamPath+"?client_id="+tc.filter, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("X-Client-ID", tc.caller)
+			res, err := srv.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res.Body.Close()
+			if res.StatusCode != tc.want {
+				t.Fatalf("status = %d, want %d", res.StatusCode, tc.want)
+			}
+		})
+	}
+}
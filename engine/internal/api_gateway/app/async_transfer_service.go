@@ -0,0 +1,206 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/app/policy"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+
+	"github.com/google/uuid"
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// AsyncConfig bounds background completion of asynchronous transfers.
+type AsyncConfig struct {
+	// MaxPending caps transfers accepted but not finished. <= 0 => 1024.
+	MaxPending int
+	// CompletionTimeout bounds each background transfer. <= 0 => 30s.
+	CompletionTimeout time.Duration
+}
+
+// AsyncTransferService accepts transfers for background completion (respond-async)
+// and answers status polls for them.
+type AsyncTransferService struct {
+	statuses outbound.TransferStatuses
+	admit    policy.TransferHandler // admission stages around accept
+	transfer policy.TransferHandler
+	cfg      AsyncConfig
+	pending  chan struct{} // semaphore of in-flight background transfers
+	logger   platform.Logger
+}
+
+// NewAsyncTransferService creates a new AsyncTransferService.
+//
+// admission wraps the stages that must answer before the 202 around the service's
+// own acceptance: idempotency (a duplicate gets the first submission's transaction,
+// a reused key is refused) and the rate limit. nil admits every valid transfer.
+// transfer is the composed handler the background completion runs through: the
+// remaining stages, without idempotency (admission applied it) and without the
+// synchronous Timeout stage (CompletionTimeout replaces it).
+func NewAsyncTransferService(
+	statuses outbound.TransferStatuses,
+	admission func(policy.TransferHandler) policy.TransferHandler,
+	transfer policy.TransferHandler,
+	cfg AsyncConfig,
+	l platform.Logger,
+) *AsyncTransferService {
+	if cfg.MaxPending <= 0 {
+		cfg.MaxPending = 1024
+	}
+	if cfg.CompletionTimeout <= 0 {
+		cfg.CompletionTimeout = 30 * time.Second
+	}
+	s := &AsyncTransferService{
+		statuses: statuses,
+		transfer: transfer,
+		cfg:      cfg,
+		pending:  make(chan struct{}, cfg.MaxPending),
+		logger:   l,
+	}
+	s.admit = s.accept
+	if admission != nil {
+		s.admit = admission(s.accept)
+	}
+	return s
+}
+
+// SubmitAsync validates the transfer and runs it through admission, which assigns
+// its transaction ID and completes it in the background. The returned result has
+// status inbound.ResultStatusAccepted; the outcome is polled with Status.
+//
+// Admission answers synchronously: a rate-limited transfer or a reused key is
+// refused here, and a duplicate of an earlier submission gets that submission's
+// record instead of a second execution: the accepted transaction while it runs,
+// its stored outcome once it has finished (inbound.Replayed reports both).
+func (s *AsyncTransferService) SubmitAsync(ctx policy.Plugins, meta hexa_inbound.RequestMeta, cmd inbound.TransferCommand) (inbound.TransferResult, error) {
+	if err := validate(cmd); err != nil {
+		return inbound.TransferResult{}, apperr.Invalid(err.Error())
+	}
+	res, err := s.admit(ctx, meta, cmd)
+	if err != nil || res.Status() != inbound.ResultStatusAccepted {
+		return res, err // refused, or answered from the idempotency store
+	}
+	// Started only now, once admission has stored the accepted record, so the
+	// outcome recorded on completion always replaces it.
+	s.start(ctx, meta, cmd, res.TransactionID())
+	return res, nil
+}
+
+// Pending reports whether the transfer id was accepted and has not finished yet.
+func (s *AsyncTransferService) Pending(id uuid.UUID) bool {
+	st, ok := s.statuses.Get(id)
+	return ok && st.State == outbound.TransferPending
+}
+
+// accept reserves a pending slot and assigns the transfer's transaction ID. It is
+// the innermost handler of admission; SubmitAsync starts the completion.
+func (s *AsyncTransferService) accept(_ policy.Plugins, meta hexa_inbound.RequestMeta, _ inbound.TransferCommand) (inbound.TransferResult, error) {
+	select {
+	case s.pending <- struct{}{}:
+	default:
+		return inbound.TransferResult{}, apperr.Overloaded("too many pending async transfers")
+	}
+
+	id := uuid.New()
+	s.statuses.Put(outbound.TransferStatus{
+		TransactionID: id,
+		ClientID:      meta.ClientID,
+		State:         outbound.TransferPending,
+		AcceptedAt:    time.Now().UTC(),
+	})
+	return inbound.NewTransferResult(id, inbound.ResultStatusAccepted, "accepted"), nil
+}
+
+// start completes an accepted transfer in the background and frees its pending
+// slot when done.
+func (s *AsyncTransferService) start(ctx policy.Plugins, meta hexa_inbound.RequestMeta, cmd inbound.TransferCommand, id uuid.UUID) {
+	// The client is gone once we answer; detach from its cancellation, keep its values.
+	bg, cancel := context.WithTimeoutCause(context.WithoutCancel(ctx), s.cfg.CompletionTimeout, policy.ErrRouteTimeout)
+	bg = outbound.WithTransactionID(bg, id)
+	if meta.ClientID != "" {
+		bg = outbound.WithClientID(bg, meta.ClientID) // as the idempotency stage binds it
+	}
+	go func() {
+		defer func() { <-s.pending }()
+		defer cancel()
+		s.complete(ctx.WithContext(bg), meta, cmd, id)
+	}()
+}
+
+// complete runs the transfer and records its outcome.
+func (s *AsyncTransferService) complete(ctx policy.Plugins, meta hexa_inbound.RequestMeta, cmd inbound.TransferCommand, id uuid.UUID) {
+	st, _ := s.statuses.Get(id)
+	defer func() {
+		if rec := recover(); rec != nil {
+			st.State = outbound.TransferFailed
+			st.Error = "internal error"
+			st.CompletedAt = time.Now().UTC()
+			s.statuses.Put(st)
+			s.logger.Warn("async transfer panicked",
+				platform.Field{Key: "transaction_id", Value: id.String()},
+				platform.Field{Key: "panic", Value: rec},
+			)
+		}
+	}()
+
+	res, err := s.transfer(ctx, meta, cmd)
+	if err == nil && res.Status() == inbound.ResultStatusAccepted {
+		// Drained at shutdown: the job was saved and resumes after restart under
+		// the same transaction ID (QueueRecovery); it stays pending until then.
+		s.logger.Info("async transfer deferred to restart",
+			platform.Field{Key: "transaction_id", Value: id.String()},
+		)
+		return
+	}
+	// Duplicates now replay the outcome instead of the accepted transaction.
+	policy.RecordOutcome(ctx, meta, cmd, res, err)
+	st.CompletedAt = time.Now().UTC()
+	if err != nil {
+		st.State = outbound.TransferFailed
+		st.Error = apperr.As(err).Msg
+		s.logger.Warn("async transfer failed",
+			platform.Field{Key: "transaction_id", Value: id.String()},
+			platform.Field{Key: "error", Value: err.Error()},
+		)
+	} else {
+		st.State = outbound.TransferCompleted
+		st.Result = res
+	}
+	s.statuses.Put(st)
+}
+
+// Status is a usecase that reports the state of an asynchronously submitted transfer.
+// It is scoped to the client that submitted it: another client's transfer is reported
+// as not found, so transaction IDs cannot be probed.
+func (s *AsyncTransferService) Status(_ policy.Plugins, meta hexa_inbound.RequestMeta, cmd inbound.TransferStatusCommand) (inbound.TransferStatusResult, error) {
+	if meta.ClientID == "" {
+		return inbound.TransferStatusResult{}, apperr.Invalid("missing client id")
+	}
+	id, err := uuid.Parse(cmd.TransactionID())
+	if err != nil {
+		return inbound.TransferStatusResult{}, apperr.Invalid("invalid transaction id")
+	}
+	st, ok := s.statuses.Get(id)
+	if !ok || st.ClientID != meta.ClientID {
+		return inbound.TransferStatusResult{}, apperr.NotFound("transfer not found")
+	}
+
+	resp := inbound.TransferStatusResponse{
+		TransactionID: id.String(),
+		State:         st.State.String(),
+		Error:         st.Error,
+		AcceptedAt:    st.AcceptedAt,
+	}
+	if st.State == outbound.TransferCompleted {
+		resp.Status = st.Result.Status().String()
+		resp.Message = st.Result.Message()
+	}
+	if !st.CompletedAt.IsZero() {
+		resp.CompletedAt = &st.CompletedAt
+	}
+	return inbound.NewTransferStatusResult(resp), nil
+}
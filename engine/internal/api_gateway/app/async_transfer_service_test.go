@@ -0,0 +1,55 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/app/policy"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	zap_adapter "fintech-capstone/m/v2/internal/platform/adapters/zap"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+	"fintech-capstone/m/v2/internal/transfer_status"
+
+	"github.com/google/uuid"
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+	"go.uber.org/zap"
+)
+
+func TestAsyncTransferStatusIsScopedToTheSubmittingClient(t *testing.T) {
+	statuses := transfer_status.New(t.Context(), transfer_status.Config{})
+	id := uuid.New()
+	statuses.Put(outbound.TransferStatus{
+		TransactionID: id,
+		ClientID:      "acme",
+		State:         outbound.TransferPending,
+		AcceptedAt:    time.Now(),
+	})
+	svc := NewAsyncTransferService(statuses, nil, nil, AsyncConfig{}, zap_adapter.New(zap.NewNop()))
+	ctx := policy.NewPluginsImpl(t.Context(), nil, nil, nil, nil, nil, nil, nil, nil)
+
+	for name, tc := range map[string]struct {
+		client string
+		id     string
+		code   apperr.Code
+	}{
+		"submitter":    {"acme", id.String(), apperr.CodeOK},
+		"other client": {"globex", id.String(), apperr.CodeNotFound},
+		"no client":    {"", id.String(), apperr.CodeInvalid},
+		"unknown id":   {"acme", uuid.NewString(), apperr.CodeNotFound},
+	} {
+		t.Run(name, func(t *testing.T) {
+			res, err := svc.Status(ctx, hexa_inbound.RequestMeta{ClientID: tc.client}, inbound.NewTransferStatusCommand(tc.id))
+			code := apperr.CodeOK
+			if err != nil {
+				code = apperr.As(err).Code
+			}
+			if code != tc.code {
+				t.Fatalf("code = %v, want %v (err %v)", code, tc.code, err)
+			}
+			if err == nil && res.Response().State != "pending" {
+				t.Fatalf("state = %q, want pending", res.Response().State)
+			}
+		})
+	}
+}
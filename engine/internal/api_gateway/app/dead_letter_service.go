@@ -0,0 +1,179 @@
+package app
+
+import (
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/app/policy"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// DeadLetterService lets clients inspect, replay and discard their dead-lettered
+// transfers. Every operation is scoped to the calling client (RequestMeta.ClientID):
+// another client's entries are reported as not found. Admins (operators,
+// housekeeping jobs) see and act on every entry.
+type DeadLetterService struct {
+	store    outbound.DeadLetters
+	commits  outbound.CommitLog
+	transfer policy.TransferHandler
+	admins   outbound.Admins
+	logger   platform.Logger
+}
+
+// replayLookback is how far before an entry's first failure the commit log must
+// reach for a replay: the transfer may have committed just before it was
+// reported as failed.
+const replayLookback = time.Minute
+
+// NewDeadLetterService creates a new DeadLetterService.
+// transfer must be the fully composed transfer handler: replays go through the same
+// policies as live traffic. commits is consulted before every replay, because the
+// idempotency store forgets outcomes on restart and after its retention while
+// dead letters do not; without it, replays are refused. admins may act on every
+// client's entries; nil => nobody may.
+func NewDeadLetterService(store outbound.DeadLetters, commits outbound.CommitLog, transfer policy.TransferHandler, admins outbound.Admins, l platform.Logger) *DeadLetterService {
+	return &DeadLetterService{store: store, commits: commits, transfer: transfer, admins: admins, logger: l}
+}
+
+// List is a usecase that returns the caller's dead-lettered transfers (every one
+// for an admin), oldest first.
+func (s *DeadLetterService) List(_ policy.Plugins, meta hexa_inbound.RequestMeta, _ inbound.DeadLetterListCommand) (inbound.DeadLetterResult, error) {
+	if meta.ClientID == "" {
+		return inbound.DeadLetterResult{}, apperr.Invalid("missing client id")
+	}
+	all := isAdmin(s.admins, meta.ClientID)
+	views := []inbound.DeadLetterView{}
+	for _, d := range s.store.List() {
+		if all || d.Meta.ClientID == meta.ClientID {
+			views = append(views, deadLetterView(d))
+		}
+	}
+	return inbound.NewDeadLetterResult(hexa_inbound.ResultStatusSuccess, "ok", views), nil
+}
+
+// Inspect is a usecase that returns a single dead-lettered transfer.
+func (s *DeadLetterService) Inspect(ctx policy.Plugins, meta hexa_inbound.RequestMeta, cmd inbound.DeadLetterCommand) (inbound.DeadLetterResult, error) {
+	d, err := s.get(ctx, meta, cmd.ID())
+	if err != nil {
+		return inbound.DeadLetterResult{}, err
+	}
+	return inbound.NewDeadLetterResult(hexa_inbound.ResultStatusSuccess, "ok", []inbound.DeadLetterView{deadLetterView(d)}), nil
+}
+
+// Replay is a usecase that re-submits a dead-lettered transfer with its original
+// idempotency key and request metadata. The entry is removed once the replay succeeds;
+// if it fails internally again, the dead-letter policy bumps its attempt count.
+//
+// A replay runs only if the commit log confirms the transfer never completed. One
+// the log shows as committed is discarded instead; one the log cannot vouch for
+// (it does not reach back to the failure) is refused and left for an operator to
+// settle by hand.
+func (s *DeadLetterService) Replay(ctx policy.Plugins, meta hexa_inbound.RequestMeta, cmd inbound.DeadLetterCommand) (inbound.TransferResult, error) {
+	d, err := s.get(ctx, meta, cmd.ID())
+	if err != nil {
+		return inbound.TransferResult{}, err
+	}
+	if err := s.neverCommitted(d); err != nil {
+		return inbound.TransferResult{}, err
+	}
+
+	res, err := s.transfer(ctx, d.Meta, d.Command())
+	if err != nil {
+		s.logger.Warn("dead letter replay failed",
+			platform.Field{Key: "id", Value: d.ID},
+			platform.Field{Key: "error", Value: err.Error()},
+		)
+		return inbound.TransferResult{}, err
+	}
+
+	if _, err := s.store.Discard(d.ID); err != nil {
+		// The transfer went through; a later replay finds it in the commit log.
+		s.logger.Error(err, platform.Field{Key: "id", Value: d.ID})
+	}
+	s.logger.Info("dead letter replayed",
+		platform.Field{Key: "id", Value: d.ID},
+		platform.Field{Key: "by", Value: meta.ClientID},
+	)
+	return res, nil
+}
+
+// neverCommitted returns nil if the commit log confirms d's transfer did not
+// commit. An entry whose transfer did commit is discarded.
+func (s *DeadLetterService) neverCommitted(d outbound.DeadLetter) error {
+	if s.commits == nil || d.IdempotencyKey == "" {
+		return apperr.Conflict("cannot confirm the transfer did not complete")
+	}
+	committed, covered := s.commits.Lookup(d.Meta.ClientID, d.IdempotencyKey, d.FirstFailedAt.Add(-replayLookback))
+	if committed {
+		if _, err := s.store.Discard(d.ID); err != nil {
+			s.logger.Error(err, platform.Field{Key: "id", Value: d.ID})
+		}
+		s.logger.Info("dead letter already committed; discarded",
+			platform.Field{Key: "id", Value: d.ID},
+		)
+		return apperr.Conflict("transfer already completed")
+	}
+	if !covered {
+		s.logger.Warn("dead letter replay refused: commit log does not reach back to it",
+			platform.Field{Key: "id", Value: d.ID},
+			platform.Field{Key: "first_failed_at", Value: d.FirstFailedAt},
+		)
+		return apperr.Conflict("cannot confirm the transfer did not complete")
+	}
+	return nil
+}
+
+// Discard is a usecase that drops a dead-lettered transfer without replaying it.
+func (s *DeadLetterService) Discard(ctx policy.Plugins, meta hexa_inbound.RequestMeta, cmd inbound.DeadLetterCommand) (inbound.DeadLetterResult, error) {
+	if _, err := s.get(ctx, meta, cmd.ID()); err != nil {
+		return inbound.DeadLetterResult{}, err
+	}
+	ok, err := s.store.Discard(cmd.ID())
+	if err != nil {
+		return inbound.DeadLetterResult{}, apperr.Wrap(apperr.CodeInternal, "discard dead letter", err)
+	}
+	if !ok {
+		return inbound.DeadLetterResult{}, apperr.NotFound("dead letter not found")
+	}
+	s.logger.Info("dead letter discarded",
+		platform.Field{Key: "id", Value: cmd.ID()},
+		platform.Field{Key: "by", Value: meta.ClientID},
+	)
+	return inbound.NewDeadLetterResult(hexa_inbound.ResultStatusSuccess, "discarded", nil), nil
+}
+
+// get returns the entry id if the caller may act on it: its own entry, or any
+// entry for an admin. Other clients' entries are not found, so their
+// IDs cannot be probed.
+func (s *DeadLetterService) get(ctx policy.Plugins, meta hexa_inbound.RequestMeta, id string) (outbound.DeadLetter, error) {
+	if meta.ClientID == "" {
+		return outbound.DeadLetter{}, apperr.Invalid("missing client id")
+	}
+	d, ok := s.store.Get(id)
+	if !ok || (d.Meta.ClientID != meta.ClientID && !isAdmin(s.admins, meta.ClientID)) {
+		return outbound.DeadLetter{}, apperr.NotFound("dead letter not found")
+	}
+	return d, nil
+}
+
+// deadLetterView maps a stored dead letter to its external view.
+func deadLetterView(d outbound.DeadLetter) inbound.DeadLetterView {
+	return inbound.DeadLetterView{
+		ID:             d.ID,
+		IdempotencyKey: d.IdempotencyKey,
+		FromAccount:    d.FromAccount,
+		ToAccount:      d.ToAccount,
+		AmountCents:    d.AmountCents,
+		ClientID:       d.Meta.ClientID,
+		RequestID:      d.Meta.RequestID,
+		TraceID:        d.Meta.TraceID,
+		Error:          d.Error,
+		Attempts:       d.Attempts,
+		FirstFailedAt:  d.FirstFailedAt,
+		LastFailedAt:   d.LastFailedAt,
+	}
+}
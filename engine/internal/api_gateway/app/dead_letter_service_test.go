@@ -0,0 +1,109 @@
+package app
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fintech-capstone/m/v2/internal/admin_allowlist"
+	"fintech-capstone/m/v2/internal/api_gateway/app/policy"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/dead_letter"
+	zap_adapter "fintech-capstone/m/v2/internal/platform/adapters/zap"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+	"fintech-capstone/m/v2/internal/worker_pool"
+
+	"github.com/google/uuid"
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+	"go.uber.org/zap"
+)
+
+// commitLog answers every Lookup with the same verdict.
+type commitLog struct{ committed, covered bool }
+
+func (commitLog) Committed(string, string) error { return nil }
+func (c commitLog) Lookup(string, string, time.Time) (bool, bool) {
+	return c.committed, c.covered
+}
+
+func TestDeadLetterReplayRunsOnlyTransfersTheCommitLogShowsNeverCompleted(t *testing.T) {
+	for name, tc := range map[string]struct {
+		commits  *commitLog
+		replayed bool
+		kept     bool
+	}{
+		"never committed": {commits: &commitLog{covered: true}, replayed: true},
+		"committed":       {commits: &commitLog{committed: true, covered: true}},
+		"not covered":     {commits: &commitLog{}, kept: true},
+		"no commit log":   {kept: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			store, err := dead_letter.NewFileStore(filepath.Join(t.TempDir(), "dead_letters.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			meta := hexa_inbound.RequestMeta{ClientID: "acme"}
+			if err := store.Capture(meta, inbound.NewTransferCommand("a", "b", 100, "k1"), apperr.Wrap(apperr.CodeInternal, "submit transfer", errors.New("disk full"))); err != nil {
+				t.Fatal(err)
+			}
+			id := store.List()[0].ID
+
+			calls := 0
+			transfer := func(policy.Plugins, hexa_inbound.RequestMeta, inbound.TransferCommand) (inbound.TransferResult, error) {
+				calls++
+				return inbound.NewTransferResult(uuid.New(), hexa_inbound.ResultStatusSuccess, "ok"), nil
+			}
+			var commits outbound.CommitLog
+			if tc.commits != nil {
+				commits = *tc.commits
+			}
+			svc := NewDeadLetterService(store, commits, transfer, nil, zap_adapter.New(zap.NewNop()))
+
+			ctx := policy.NewPluginsImpl(t.Context(), nil, nil, nil, nil, store, nil, nil, nil)
+			_, err = svc.Replay(ctx, meta, inbound.NewDeadLetterCommand(id))
+			if replayed := calls == 1; replayed != tc.replayed {
+				t.Fatalf("replayed = %v, want %v (err %v)", replayed, tc.replayed, err)
+			}
+			if !tc.replayed && apperr.As(err).Code != apperr.CodeConflict {
+				t.Fatalf("err = %v, want a conflict", err)
+			}
+			if _, kept := store.Get(id); kept != tc.kept {
+				t.Fatalf("entry kept = %v, want %v", kept, tc.kept)
+			}
+		})
+	}
+}
+
+func TestDeadLetterAdminsComeFromTheAllowlistNotThePriorityTier(t *testing.T) {
+	store, err := dead_letter.NewFileStore(filepath.Join(t.TempDir(), "dead_letters.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, client := range []string{"acme", "globex"} {
+		meta := hexa_inbound.RequestMeta{ClientID: client}
+		if err := store.Capture(meta, inbound.NewTransferCommand("a", "b", 100, client), apperr.Wrap(apperr.CodeInternal, "submit transfer", errors.New("disk full"))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	svc := NewDeadLetterService(store, nil, nil, admin_allowlist.New("ops"), zap_adapter.New(zap.NewNop()))
+	tiers := worker_pool.Tiers{Clients: map[string]outbound.PriorityClass{"acme": outbound.PriorityInternal}}
+	ctx := policy.NewPluginsImpl(t.Context(), nil, nil, nil, tiers, store, nil, nil, nil)
+
+	for caller, want := range map[string]int{"acme": 1, "globex": 1, "ops": 2, "": 0} {
+		res, err := svc.List(ctx, hexa_inbound.RequestMeta{ClientID: caller}, inbound.DeadLetterListCommand{})
+		if caller == "" {
+			if err == nil || apperr.As(err).Code != apperr.CodeInvalid {
+				t.Fatalf("anonymous caller: err = %v, want invalid", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := len(res.Entries()); got != want {
+			t.Fatalf("%q sees %d dead letters, want %d", caller, got, want)
+		}
+	}
+}
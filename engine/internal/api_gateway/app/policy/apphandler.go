@@ -17,13 +17,30 @@ type Plugins interface {
 	Limiter() outbound.Limiter
 	Timeout() time.Duration
 	Idempotency() outbound.Idempotency[hexa_inbound.Result]
+	Prioritizer() outbound.Prioritizer
+	DeadLetters() outbound.DeadLetters
+	Events() outbound.EventPublisher
+	CircuitBreaker() outbound.CircuitBreaker
+	Retrier() outbound.Retrier
+	// WithContext returns plugins bound to ctx, so policies can hand request
+	// scoped values (e.g. the priority class) to the handlers they wrap.
+	// Transports bind each request's context here before calling the handler, so
+	// client disconnects and deadlines reach the use case and the dispatcher.
+	WithContext(ctx context.Context) Plugins
 }
 
+// PluginsImpl carries the shared capabilities. Its ctx is the process context
+// until a transport binds a request with WithContext.
 type PluginsImpl struct {
 	ctx         context.Context
 	metrics     outbound.Metrics
 	limiter     outbound.Limiter
 	idempotency outbound.Idempotency[hexa_inbound.Result]
+	prioritizer outbound.Prioritizer
+	deadLetters outbound.DeadLetters
+	events      outbound.EventPublisher
+	breaker     outbound.CircuitBreaker
+	retrier     outbound.Retrier
 }
 
 func NewPluginsImpl(
@@ -31,12 +48,22 @@ func NewPluginsImpl(
 	metrics outbound.Metrics,
 	limiter outbound.Limiter,
 	idempotency outbound.Idempotency[hexa_inbound.Result],
+	prioritizer outbound.Prioritizer,
+	deadLetters outbound.DeadLetters,
+	events outbound.EventPublisher,
+	breaker outbound.CircuitBreaker,
+	retrier outbound.Retrier,
 ) *PluginsImpl {
 	return &PluginsImpl{
 		ctx:         ctx,
 		metrics:     metrics,
 		limiter:     limiter,
 		idempotency: idempotency,
+		prioritizer: prioritizer,
+		deadLetters: deadLetters,
+		events:      events,
+		breaker:     breaker,
+		retrier:     retrier,
 	}
 }
 
@@ -46,6 +73,18 @@ func (c *PluginsImpl) Timeout() time.Duration    { return 2 * time.Second }
 func (c *PluginsImpl) Idempotency() outbound.Idempotency[hexa_inbound.Result] {
 	return c.idempotency
 }
+func (c *PluginsImpl) Prioritizer() outbound.Prioritizer { return c.prioritizer }
+func (c *PluginsImpl) DeadLetters() outbound.DeadLetters { return c.deadLetters }
+func (c *PluginsImpl) Events() outbound.EventPublisher   { return c.events }
+func (c *PluginsImpl) CircuitBreaker() outbound.CircuitBreaker {
+	return c.breaker
+}
+func (c *PluginsImpl) Retrier() outbound.Retrier { return c.retrier }
+func (c *PluginsImpl) WithContext(ctx context.Context) Plugins {
+	cp := *c
+	cp.ctx = ctx
+	return &cp
+}
 func (p *PluginsImpl) Deadline() (time.Time, bool) { return p.ctx.Deadline() }
 func (p *PluginsImpl) Done() <-chan struct{}       { return p.ctx.Done() }
 func (p *PluginsImpl) Err() error                  { return p.ctx.Err() }
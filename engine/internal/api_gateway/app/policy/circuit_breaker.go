@@ -0,0 +1,39 @@
+package policy
+
+import (
+	"context"
+	"errors"
+
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// CircuitBreaker is a middleware that fails fast while the downstream circuit is
+// open and reports every admitted call's outcome back to the breaker. A call cut
+// short by the gateway's own budget (ErrRouteTimeout) reports a timeout, which
+// counts as a failure. A call cut short by the caller, who cancelled or whose own
+// deadline passed, reports the context error instead, and the breaker does not
+// hold that against the dependency.
+func CircuitBreaker(next AppHandler) AppHandler {
+	return func(ctx Plugins, meta hexa_inbound.RequestMeta, cmd hexa_inbound.Command) (hexa_inbound.Result, error) {
+		b := ctx.CircuitBreaker()
+		if b == nil {
+			return next(ctx, meta, cmd)
+		}
+		done, err := b.Allow()
+		if err != nil {
+			ctx.Metrics().IncCircuitRejected()
+			var zero hexa_inbound.Result
+			return zero, err
+		}
+		res, err := next(ctx, meta, cmd)
+		switch {
+		case err == nil || ctx.Err() == nil:
+			done(err)
+		case errors.Is(context.Cause(ctx), ErrRouteTimeout):
+			done(ErrRouteTimeout)
+		default:
+			done(ctx.Err())
+		}
+		return res, err
+	}
+}
@@ -0,0 +1,67 @@
+package policy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/circuit_breaker"
+
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// reportingBreaker admits every call and records what it reports.
+type reportingBreaker struct{ reported []error }
+
+func (b *reportingBreaker) Allow() (func(error), error) {
+	return func(err error) { b.reported = append(b.reported, err) }, nil
+}
+
+// stuck waits for its context, like a dispatcher call that never answers.
+func stuck(ctx Plugins, _ hexa_inbound.RequestMeta, _ hexa_inbound.Command) (hexa_inbound.Result, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestCircuitBreakerCountsOnlyTheGatewaysOwnTimeout(t *testing.T) {
+	for name, tc := range map[string]struct {
+		ctx  func() (context.Context, context.CancelFunc)
+		want circuit_breaker.Outcome
+	}{
+		"route timeout": {func() (context.Context, context.CancelFunc) {
+			return context.WithTimeoutCause(t.Context(), time.Millisecond, ErrRouteTimeout)
+		}, circuit_breaker.Failure},
+		"caller deadline": {func() (context.Context, context.CancelFunc) {
+			return context.WithTimeout(t.Context(), time.Millisecond)
+		}, circuit_breaker.Ignored},
+		"caller cancel": {func() (context.Context, context.CancelFunc) {
+			ctx, cancel := context.WithCancel(t.Context())
+			time.AfterFunc(time.Millisecond, cancel)
+			return ctx, cancel
+		}, circuit_breaker.Ignored},
+		// The caller's deadline comes first even though the route budget is set.
+		"caller deadline inside the route budget": {func() (context.Context, context.CancelFunc) {
+			caller, cancelCaller := context.WithTimeout(t.Context(), time.Millisecond)
+			ctx, cancel := context.WithTimeoutCause(caller, time.Hour, ErrRouteTimeout)
+			return ctx, func() { cancel(); cancelCaller() }
+		}, circuit_breaker.Ignored},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := tc.ctx()
+			defer cancel()
+			b := &reportingBreaker{}
+			plugins := NewPluginsImpl(ctx, nil, nil, nil, nil, nil, nil, b, nil)
+
+			if _, err := CircuitBreaker(stuck)(plugins, hexa_inbound.RequestMeta{}, inbound.NewTransferCommand("a", "b", 100, "k1")); err == nil {
+				t.Fatal("a call cut short returned no error")
+			}
+			if len(b.reported) != 1 {
+				t.Fatalf("reported %d outcomes, want 1", len(b.reported))
+			}
+			if got := circuit_breaker.DefaultClassify(b.reported[0]); got != tc.want {
+				t.Fatalf("reported %v, classified %v; want %v", b.reported[0], got, tc.want)
+			}
+		})
+	}
+}
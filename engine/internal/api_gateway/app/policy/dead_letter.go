@@ -0,0 +1,46 @@
+package policy
+
+import (
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+type TransferHandler = hexa_inbound.UnaryHandler[Plugins, inbound.TransferCommand, inbound.TransferResult]
+
+// DeadLetter is a middleware that parks transfers failing for internal reasons
+// (storage errors, worker panics) in the dead-letter store for later replay.
+// Client errors, rejections and callers that gave up are not captured.
+//
+// A success discards the client's entry for the same key, if any: the client's
+// own retry went through, so there is nothing left to replay.
+func DeadLetter(next TransferHandler) TransferHandler {
+	return func(ctx Plugins, meta hexa_inbound.RequestMeta, cmd inbound.TransferCommand) (inbound.TransferResult, error) {
+		res, err := next(ctx, meta, cmd)
+		if ctx.DeadLetters() == nil {
+			return res, err
+		}
+		if err == nil {
+			if cmd.IdempotencyKey() != "" {
+				if _, derr := ctx.DeadLetters().Discard(outbound.ScopedIdempotencyKey(meta.ClientID, cmd.IdempotencyKey())); derr != nil {
+					ctx.Metrics().IncDeadLetterFailure()
+				}
+			}
+			return res, err
+		}
+		if ctx.Err() != nil {
+			return res, err
+		}
+		if apperr.As(err).Code != apperr.CodeInternal {
+			return res, err
+		}
+		if cerr := ctx.DeadLetters().Capture(meta, cmd, err); cerr != nil {
+			ctx.Metrics().IncDeadLetterFailure()
+			return res, err
+		}
+		ctx.Metrics().IncDeadLettered()
+		return res, err
+	}
+}
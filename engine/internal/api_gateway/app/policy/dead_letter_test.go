@@ -0,0 +1,41 @@
+package policy
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/dead_letter"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+
+	"github.com/google/uuid"
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+func TestDeadLetterDiscardsTheEntryWhenTheClientsRetrySucceeds(t *testing.T) {
+	store, err := dead_letter.NewFileStore(filepath.Join(t.TempDir(), "dead_letters.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cause := apperr.Wrap(apperr.CodeInternal, "submit transfer", errors.New("disk full"))
+	acme := hexa_inbound.RequestMeta{ClientID: "acme"}
+	globex := hexa_inbound.RequestMeta{ClientID: "globex"}
+	cmd := inbound.NewTransferCommand("a", "b", 100, "k1")
+	for _, meta := range []hexa_inbound.RequestMeta{acme, globex} {
+		if err := store.Capture(meta, cmd, cause); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h := DeadLetter(func(Plugins, hexa_inbound.RequestMeta, inbound.TransferCommand) (inbound.TransferResult, error) {
+		return inbound.NewTransferResult(uuid.New(), hexa_inbound.ResultStatusSuccess, "ok"), nil
+	})
+	if _, err := h(NewPluginsImpl(t.Context(), nil, nil, nil, nil, store, nil, nil, nil), acme, cmd); err != nil {
+		t.Fatal(err)
+	}
+	left := store.List()
+	if len(left) != 1 || left[0].Meta.ClientID != "globex" {
+		t.Fatalf("left %+v, want only globex's entry", left)
+	}
+}
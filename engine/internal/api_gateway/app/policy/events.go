@@ -0,0 +1,57 @@
+package policy
+
+import (
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+
+	"github.com/google/uuid"
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// Events is a middleware that publishes transfer lifecycle events (settled, rejected)
+// for webhook delivery. Idempotent replays never reach it, so each transfer is
+// announced once; internal failures are left to the dead-letter stage.
+func Events(next TransferHandler) TransferHandler {
+	return func(ctx Plugins, meta hexa_inbound.RequestMeta, cmd inbound.TransferCommand) (inbound.TransferResult, error) {
+		res, err := next(ctx, meta, cmd)
+		if ctx.Events() == nil {
+			return res, err
+		}
+
+		ev := outbound.TransferEvent{
+			ID:             uuid.NewString(),
+			ClientID:       meta.ClientID,
+			FromAccount:    cmd.FromAccount(),
+			ToAccount:      cmd.ToAccount(),
+			AmountCents:    cmd.AmountCents(),
+			IdempotencyKey: cmd.IdempotencyKey(),
+			OccurredAt:     time.Now().UTC(),
+		}
+		switch {
+		case err == nil && res.Status() == hexa_inbound.ResultStatusSuccess:
+			ev.Type = outbound.EventTransferSettled
+			ev.TransactionID = res.TransactionID().String()
+			ev.Status = res.Status().String()
+			ev.Message = res.Message()
+		case err == nil && res.Status() == hexa_inbound.ResultStatusRejected:
+			ev.Type = outbound.EventTransferRejected
+			ev.TransactionID = res.TransactionID().String()
+			ev.Status = res.Status().String()
+			ev.Message = res.Message()
+		case err != nil && (apperr.As(err).Code == apperr.CodeInvalid || apperr.As(err).Code == apperr.CodeConflict):
+			ev.Type = outbound.EventTransferRejected
+			ev.Status = hexa_inbound.ResultStatusRejected.String()
+			ev.Message = apperr.As(err).Msg
+		default:
+			return res, err
+		}
+
+		if perr := ctx.Events().Publish(ev); perr != nil {
+			ctx.Metrics().IncEventPublishFailure()
+		}
+		return res, err
+	}
+}
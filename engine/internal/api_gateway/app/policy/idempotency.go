@@ -2,8 +2,11 @@ package policy
 
 import (
 	"fmt"
+	"time"
 
 	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform/apperr"
 
 	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
 )
@@ -11,21 +14,154 @@ import (
 type IdempotentHandler = hexa_inbound.UnaryHandler[Plugins, inbound.IdempotentCommand, hexa_inbound.Result]
 
 // Idempotency is a middlelare that provides idempotency support for commands implementing IdempotentCommand.
+// A key reused with a different payload (see inbound.Fingerprinted) is rejected with
+// CodeConflict instead of replaying the result of the original request. With an
+// outbound.ClaimIdempotency store, concurrent duplicates execute once: they wait
+// for the first request's result, at most the route timeout, and then get
+// CodeInProgress with a Retry-After hint.
+//
+// Keys are scoped by RequestMeta.ClientID (outbound.ScopedIdempotencyKey), and the
+// client ID is bound to the context so queued jobs keep their scope across restarts.
+// Replays carry status duplicate (inbound.Replayable) for transports to flag.
+//
+// Failures are offered to the store too: it keeps terminal ones the route opts into
+// (e.g. a rejected transfer), so a retry gets the same answer instead of running
+// again, and replays them marked apperr.Error.Replayed with the original code.
 func Idempotency(next IdempotentHandler) IdempotentHandler {
 	return func(ctx Plugins, meta hexa_inbound.RequestMeta, cmd inbound.IdempotentCommand) (hexa_inbound.Result, error) {
 		fmt.Println("idempotency")
+		if cmd.IdempotencyKey() == "" {
+			return next(ctx, meta, cmd) // nothing to dedupe on; the use case rejects it
+		}
+		key := outbound.ScopedIdempotencyKey(meta.ClientID, cmd.IdempotencyKey())
+		fp := fingerprint(cmd)
+		if meta.ClientID != "" {
+			ctx = ctx.WithContext(outbound.WithClientID(ctx, meta.ClientID))
+		}
 		// WARN: can store be nil?
-		if cached, ok := ctx.Idempotency().Get(cmd.IdempotencyKey()); ok {
-			ctx.Metrics().IncIdempotentHit()
-			return cached, nil
+		if cs, ok := ctx.Idempotency().(outbound.ClaimIdempotency[hexa_inbound.Result]); ok {
+			return claimed(ctx, cs, meta, cmd, key, fp, next)
+		}
+		if rec, ok := lookup(ctx.Idempotency(), key); ok {
+			return replay(ctx, rec, fp)
 		}
 
 		res, err := next(ctx, meta, cmd)
-		// WARN: haven't checked if idempotencykey can be empty
-		if err == nil {
-			ctx.Idempotency().Store(cmd.IdempotencyKey(), res)
-		}
+		store(ctx.Idempotency(), meta.Target, key, outcome(res, err, fp))
 
 		return res, err
 	}
 }
+
+// claimed runs cmd once per key: the owner of the claim executes and stores the
+// result, duplicates wait for the claim to end and look again. A duplicate whose
+// wait runs out gets errInProgress rather than a second execution.
+func claimed(ctx Plugins, cs outbound.ClaimIdempotency[hexa_inbound.Result], meta hexa_inbound.RequestMeta, cmd inbound.IdempotentCommand, key, fp string, next IdempotentHandler) (hexa_inbound.Result, error) {
+	var expired <-chan time.Time
+	if ctx.Timeout() > 0 {
+		t := time.NewTimer(ctx.Timeout())
+		defer t.Stop()
+		expired = t.C
+	}
+	for {
+		c := cs.Claim(key, fp)
+		switch {
+		case c.Record != nil:
+			return replay(ctx, *c.Record, fp)
+		case c.Release != nil:
+			defer c.Release() // after storing, so waiters find the record
+			res, err := next(ctx, meta, cmd)
+			cs.StoreRecord(meta.Target, key, outcome(res, err, fp))
+			return res, err
+		case c.Fingerprint != "" && fp != "" && c.Fingerprint != fp:
+			return nil, errKeyReused
+		}
+		select {
+		case <-c.Done:
+			// Released or expired: the result is stored, or the key is free again
+			// (the first request failed or its route keeps nothing).
+		case <-expired:
+			return nil, errInProgress
+		case <-ctx.Done():
+			return nil, errInProgress
+		}
+	}
+}
+
+var (
+	// errKeyReused tells the client its request is wrong: retrying it fails the
+	// same way, only a new key (or the original payload) helps. It is a conflict,
+	// unlike errInProgress, which is worth retrying.
+	errKeyReused = apperr.Conflict("idempotency key reused with a different request")
+	// errInProgress tells a duplicate to retry shortly: by then the first request
+	// has usually finished and its result is replayed.
+	errInProgress = apperr.InProgress("request in progress").WithRetryAfter(time.Second)
+)
+
+// replay answers from a stored record, unless the record is for another payload.
+func replay(ctx Plugins, rec outbound.IdempotencyRecord[hexa_inbound.Result], fp string) (hexa_inbound.Result, error) {
+	if rec.Fingerprint != "" && fp != "" && rec.Fingerprint != fp {
+		return nil, errKeyReused
+	}
+	ctx.Metrics().IncIdempotentHit()
+	if rec.Err != nil {
+		return nil, rec.Err
+	}
+	if r, ok := rec.Result.(inbound.Replayable); ok {
+		return r.Replay(), nil
+	}
+	return rec.Result, nil
+}
+
+// RecordOutcome stores the outcome of cmd, which the Idempotency stage admitted
+// earlier, under its key, replacing the record stored at admission. Asynchronous
+// transfers use it: admission stores the accepted transaction, and the background
+// completion, which runs without the stage, records how it ended. A failure the
+// store does not keep leaves the accepted record in place.
+func RecordOutcome(ctx Plugins, meta hexa_inbound.RequestMeta, cmd inbound.IdempotentCommand, res hexa_inbound.Result, err error) {
+	if cmd.IdempotencyKey() == "" || ctx.Idempotency() == nil {
+		return
+	}
+	key := outbound.ScopedIdempotencyKey(meta.ClientID, cmd.IdempotencyKey())
+	store(ctx.Idempotency(), meta.Target, key, outcome(res, err, fingerprint(cmd)))
+}
+
+// outcome builds the record for a finished request. A failure keeps only its code
+// and message, ready to replay.
+func outcome(res hexa_inbound.Result, err error, fp string) outbound.IdempotencyRecord[hexa_inbound.Result] {
+	if err != nil {
+		return outbound.IdempotencyRecord[hexa_inbound.Result]{Err: apperr.As(err).Replay(), Fingerprint: fp}
+	}
+	return outbound.IdempotencyRecord[hexa_inbound.Result]{Result: res, Fingerprint: fp}
+}
+
+// fingerprint returns the command's fingerprint, or "" when it has none.
+func fingerprint(cmd inbound.IdempotentCommand) string {
+	if f, ok := cmd.(inbound.Fingerprinted); ok {
+		return f.Fingerprint()
+	}
+	return ""
+}
+
+// lookup returns the stored record for key. Stores without records yield an
+// empty fingerprint, which matches any request.
+func lookup(s outbound.Idempotency[hexa_inbound.Result], key string) (outbound.IdempotencyRecord[hexa_inbound.Result], bool) {
+	if rs, ok := s.(outbound.RecordIdempotency[hexa_inbound.Result]); ok {
+		return rs.GetRecord(key)
+	}
+	res, ok := s.Get(key)
+	return outbound.IdempotencyRecord[hexa_inbound.Result]{Result: res}, ok
+}
+
+// store keeps rec for the route's retention window when the store supports records
+// (the store decides which failures to keep), and only a successful result for its
+// default TTL otherwise.
+func store(s outbound.Idempotency[hexa_inbound.Result], route, key string, rec outbound.IdempotencyRecord[hexa_inbound.Result]) {
+	if rs, ok := s.(outbound.RecordIdempotency[hexa_inbound.Result]); ok {
+		rs.StoreRecord(route, key, rec)
+		return
+	}
+	if rec.Err == nil {
+		s.Store(key, rec.Result)
+	}
+}
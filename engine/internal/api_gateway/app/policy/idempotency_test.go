@@ -0,0 +1,222 @@
+package policy
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/idempotency_store"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+
+	"github.com/google/uuid"
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// hitMetrics counts idempotent hits; the stage records nothing else.
+type hitMetrics struct {
+	outbound.Metrics
+	hits atomic.Int64
+}
+
+func (m *hitMetrics) IncIdempotentHit() { m.hits.Add(1) }
+
+type call struct {
+	client string
+	cmd    inbound.TransferCommand
+}
+
+// idempotencyStore returns a store keeping rejected transfers on the transfer route.
+func idempotencyStore(t *testing.T, claimTTL time.Duration) *idempotency_store.Store[hexa_inbound.Result] {
+	t.Helper()
+	return idempotency_store.New(t.Context(), idempotency_store.Config[hexa_inbound.Result]{
+		Failures: map[string][]apperr.Code{"transfer": {apperr.CodeInvalid}},
+		ClaimTTL: claimTTL,
+	})
+}
+
+func idempotencyPlugins(ctx context.Context, store outbound.Idempotency[hexa_inbound.Result]) Plugins {
+	return NewPluginsImpl(ctx, &hitMetrics{}, nil, store, nil, nil, nil, nil, nil)
+}
+
+// execute answers each call with a new transaction, or with errs in turn.
+func execute(calls *atomic.Int64, errs ...error) IdempotentHandler {
+	return func(Plugins, hexa_inbound.RequestMeta, inbound.IdempotentCommand) (hexa_inbound.Result, error) {
+		n := int(calls.Add(1))
+		if n <= len(errs) && errs[n-1] != nil {
+			return nil, errs[n-1]
+		}
+		return inbound.NewTransferResult(uuid.New(), hexa_inbound.ResultStatusSuccess, "ok"), nil
+	}
+}
+
+func TestIdempotencyAnswersRepeatsFromTheStore(t *testing.T) {
+	k1 := inbound.NewTransferCommand("a", "b", 100, "k1")
+	rejected := apperr.Invalid("insufficient funds")
+	for name, tc := range map[string]struct {
+		errs     []error
+		second   call
+		runs     int64
+		replayed bool
+		code     apperr.Code // of the second call
+	}{
+		"same payload":         {second: call{"acme", k1}, runs: 1, replayed: true},
+		"different payload":    {second: call{"acme", inbound.NewTransferCommand("a", "b", 999, "k1")}, runs: 1, code: apperr.CodeConflict},
+		"another client":       {second: call{"globex", k1}, runs: 2},
+		"stored failure":       {errs: []error{rejected}, second: call{"acme", k1}, runs: 1, replayed: true, code: apperr.CodeInvalid},
+		"failure not kept":     {errs: []error{apperr.Overloaded("busy")}, second: call{"acme", k1}, runs: 2},
+		"no key":               {second: call{"acme", inbound.NewTransferCommand("a", "b", 100, "")}, runs: 2},
+		"failure, new payload": {errs: []error{rejected}, second: call{"acme", inbound.NewTransferCommand("a", "c", 100, "k1")}, runs: 1, code: apperr.CodeConflict},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var runs atomic.Int64
+			h := Idempotency(execute(&runs, tc.errs...))
+			ctx := idempotencyPlugins(t.Context(), idempotencyStore(t, 0))
+
+			first, firstErr := h(ctx, hexa_inbound.RequestMeta{ClientID: "acme", Target: "transfer"}, k1)
+			res, err := h(ctx, hexa_inbound.RequestMeta{ClientID: tc.second.client, Target: "transfer"}, tc.second.cmd)
+
+			if got := runs.Load(); got != tc.runs {
+				t.Fatalf("executed %d times, want %d", got, tc.runs)
+			}
+			if tc.code != apperr.CodeOK {
+				e := apperr.As(err)
+				if e == nil || e.Code != tc.code {
+					t.Fatalf("err = %v, want code %v", err, tc.code)
+				}
+				if e.Replayed != tc.replayed {
+					t.Fatalf("replayed = %v, want %v", e.Replayed, tc.replayed)
+				}
+				if tc.replayed && (firstErr == nil || e.Msg != apperr.As(firstErr).Msg) {
+					t.Fatalf("replayed %q, want the original %v", e.Msg, firstErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v, want success", err)
+			}
+			if inbound.Replayed(res) != tc.replayed {
+				t.Fatalf("replayed = %v, want %v", inbound.Replayed(res), tc.replayed)
+			}
+			if tc.replayed && res.(inbound.TransferResult).TransactionID() != first.(inbound.TransferResult).TransactionID() {
+				t.Fatal("replay carries another transaction")
+			}
+		})
+	}
+}
+
+// claimHeld returns a handler whose first call blocks until release is closed,
+// holding the key's claim meanwhile.
+func claimHeld(runs *atomic.Int64, release <-chan struct{}) IdempotentHandler {
+	return func(Plugins, hexa_inbound.RequestMeta, inbound.IdempotentCommand) (hexa_inbound.Result, error) {
+		if runs.Add(1) == 1 {
+			<-release
+		}
+		return inbound.NewTransferResult(uuid.New(), hexa_inbound.ResultStatusSuccess, "ok"), nil
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestIdempotencyRunsConcurrentDuplicatesOnce(t *testing.T) {
+	store := idempotencyStore(t, 0)
+	var runs atomic.Int64
+	release := make(chan struct{})
+	h := Idempotency(claimHeld(&runs, release))
+	ctx := idempotencyPlugins(t.Context(), store)
+	meta := hexa_inbound.RequestMeta{ClientID: "acme", Target: "transfer"}
+	cmd := inbound.NewTransferCommand("a", "b", 100, "k1")
+
+	const n = 5
+	results := make([]hexa_inbound.Result, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := h(ctx, meta, cmd)
+			if err != nil {
+				t.Errorf("call %d: %v", i, err)
+			}
+			results[i] = res
+		}()
+		if i == 0 {
+			waitFor(t, func() bool { return store.Stats().InFlight == 1 })
+		}
+	}
+	waitFor(t, func() bool { return store.Stats().Contended >= n-1 })
+	close(release)
+	wg.Wait()
+
+	if got := runs.Load(); got != 1 {
+		t.Fatalf("executed %d times, want 1", got)
+	}
+	id := results[0].(inbound.TransferResult).TransactionID()
+	for i, res := range results[1:] {
+		if !inbound.Replayed(res) || res.(inbound.TransferResult).TransactionID() != id {
+			t.Fatalf("duplicate %d got %v, want a replay of %v", i+1, res, id)
+		}
+	}
+}
+
+func TestIdempotencyDuplicateWhoseCallerLeavesIsInProgress(t *testing.T) {
+	store := idempotencyStore(t, 0)
+	var runs atomic.Int64
+	release := make(chan struct{})
+	defer close(release)
+	h := Idempotency(claimHeld(&runs, release))
+	meta := hexa_inbound.RequestMeta{ClientID: "acme", Target: "transfer"}
+	cmd := inbound.NewTransferCommand("a", "b", 100, "k1")
+
+	go h(idempotencyPlugins(t.Context(), store), meta, cmd)
+	waitFor(t, func() bool { return store.Stats().InFlight == 1 })
+
+	ctx, cancel := context.WithCancel(t.Context())
+	go func() {
+		waitFor(t, func() bool { return store.Stats().Contended == 1 })
+		cancel()
+	}()
+	_, err := h(idempotencyPlugins(ctx, store), meta, cmd)
+	if e := apperr.As(err); e == nil || e.Code != apperr.CodeInProgress || e.RetryAfter != time.Second {
+		t.Fatalf("err = %v, want in progress with Retry-After 1s", err)
+	}
+	if got := runs.Load(); got != 1 {
+		t.Fatalf("executed %d times, want 1", got)
+	}
+}
+
+func TestIdempotencyDuplicateTakesOverAnExpiredClaim(t *testing.T) {
+	store := idempotencyStore(t, 20*time.Millisecond)
+	var runs atomic.Int64
+	release := make(chan struct{})
+	defer close(release)
+	h := Idempotency(claimHeld(&runs, release)) // the owner never finishes
+	meta := hexa_inbound.RequestMeta{ClientID: "acme", Target: "transfer"}
+	cmd := inbound.NewTransferCommand("a", "b", 100, "k1")
+
+	go h(idempotencyPlugins(t.Context(), store), meta, cmd)
+	waitFor(t, func() bool { return store.Stats().InFlight == 1 })
+
+	res, err := h(idempotencyPlugins(t.Context(), store), meta, cmd)
+	if err != nil || inbound.Replayed(res) {
+		t.Fatalf("got %v, %v; want the duplicate to execute once the claim expired", res, err)
+	}
+	if got := runs.Load(); got != 2 {
+		t.Fatalf("executed %d times, want 2", got)
+	}
+	if got := store.Stats().ClaimsExpired; got != 1 {
+		t.Fatalf("claims expired = %d, want 1", got)
+	}
+}
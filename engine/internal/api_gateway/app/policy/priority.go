@@ -0,0 +1,26 @@
+package policy
+
+import (
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+
+	"github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// Priority is a middleware that assigns the dispatcher priority class of a request.
+// The client's configured tier decides. A class the client asked for (explicit
+// header or gRPC metadata, outbound.RequestedPriorityFrom) is granted only at or
+// below that tier, so a client can yield to others but never jump ahead of them.
+// A class already set on the context by the gateway itself (e.g. a resumed job's
+// saved class) wins.
+func Priority(next AppHandler) AppHandler {
+	return func(ctx Plugins, meta inbound.RequestMeta, cmd inbound.Command) (inbound.Result, error) {
+		if _, ok := outbound.PriorityFrom(ctx); ok || ctx.Prioritizer() == nil {
+			return next(ctx, meta, cmd)
+		}
+		class := ctx.Prioritizer().Classify(meta.ClientID)
+		if asked, ok := outbound.RequestedPriorityFrom(ctx); ok && asked.Within(class) {
+			class = asked
+		}
+		return next(ctx.WithContext(outbound.WithPriority(ctx, class)), meta, cmd)
+	}
+}
@@ -0,0 +1,62 @@
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// tiers classifies clients by a fixed map; unlisted clients are interactive.
+type tiers map[string]outbound.PriorityClass
+
+func (t tiers) Classify(clientID string) outbound.PriorityClass {
+	if c, ok := t[clientID]; ok {
+		return c
+	}
+	return outbound.PriorityInteractive
+}
+
+func TestPriorityGrantsAskedClassesOnlyWithinTheTier(t *testing.T) {
+	tiers := tiers{"payouts": outbound.PriorityBulk, "sweeper": outbound.PriorityInternal}
+	for name, tc := range map[string]struct {
+		client string
+		ctx    func(context.Context) context.Context
+		want   outbound.PriorityClass
+	}{
+		"tier":          {"payouts", nil, outbound.PriorityBulk},
+		"lowered":       {"alice", asked("bulk"), outbound.PriorityBulk},
+		"same class":    {"payouts", asked("bulk"), outbound.PriorityBulk},
+		"raised":        {"payouts", asked("interactive"), outbound.PriorityBulk},
+		"raised twice":  {"sweeper", asked("interactive"), outbound.PriorityInternal},
+		"unknown class": {"alice", asked("urgent"), outbound.PriorityInteractive},
+		"set by the gateway": {"payouts", func(ctx context.Context) context.Context {
+			return outbound.WithPriority(ctx, outbound.PriorityInteractive)
+		}, outbound.PriorityInteractive},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if tc.ctx != nil {
+				ctx = tc.ctx(ctx)
+			}
+			var got outbound.PriorityClass
+			next := func(ctx Plugins, _ hexa_inbound.RequestMeta, _ hexa_inbound.Command) (hexa_inbound.Result, error) {
+				got, _ = outbound.PriorityFrom(ctx)
+				return nil, nil
+			}
+			plugins := NewPluginsImpl(ctx, nil, nil, nil, tiers, nil, nil, nil, nil)
+			Priority(next)(plugins, hexa_inbound.RequestMeta{ClientID: tc.client}, inbound.NewTransferCommand("a", "b", 100, "k1"))
+			if got != tc.want {
+				t.Fatalf("class = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// asked sets the class a client asked for, as the transports do.
+func asked(class string) func(context.Context) context.Context {
+	return func(ctx context.Context) context.Context { return outbound.WithRequestedPriority(ctx, class) }
+}
@@ -3,6 +3,7 @@ package policy
 import (
 	"fmt"
 
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
 	"fintech-capstone/m/v2/internal/platform/apperr"
 
 	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
@@ -23,14 +24,29 @@ import (
 // }
 
 // RateLimit is a middleware that enforces rate limiting based on the provided limiter.
+// An outbound.CostLimiter charges the request's cost for its route. A refusal
+// carries the decision (outbound.LimitExceeded) and its Retry-After, so
+// transports can tell the client when to come back; an admission records it on
+// the context's outbound.LimitReport, so they can report the budget left.
 func RateLimit(next AppHandler) AppHandler {
 	return func(ctx Plugins, meta hexa_inbound.RequestMeta, cmd hexa_inbound.Command) (hexa_inbound.Result, error) {
 		fmt.Println("Applying rate limit...")
 		var zero hexa_inbound.Result
-		if !ctx.Limiter().Allow(meta.ClientID) {
+		d := admit(ctx.Limiter(), meta, cmd)
+		if !d.Allowed {
 			ctx.Metrics().IncRateLimited()
-			return zero, apperr.RateLimited("rate limit exceeded")
+			return zero, apperr.Wrap(apperr.CodeRateLimited, "rate limit exceeded", outbound.LimitExceeded{LimitDecision: d}).
+				WithRetryAfter(d.RetryAfter)
 		}
+		outbound.ReportLimit(ctx, d)
 		return next(ctx, meta, cmd)
 	}
 }
+
+// admit charges cmd's cost when the limiter prices requests, else 1 token.
+func admit(l outbound.Limiter, meta hexa_inbound.RequestMeta, cmd hexa_inbound.Command) outbound.LimitDecision {
+	if cl, ok := l.(outbound.CostLimiter); ok {
+		return cl.AllowCost(meta.ClientID, meta.Target, cmd)
+	}
+	return l.Allow(meta.ClientID)
+}
@@ -0,0 +1,44 @@
+package policy
+
+import (
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// Retry is a middleware that retries transient failures (apperr.Transient) with
+// full-jitter backoff, as long as the retry budget allows. It is typed on
+// IdempotentCommand: a retry re-sends the same idempotency key, so a downstream
+// that already applied the first attempt does not apply it twice. Commands without
+// a key are not retried, and retrying stops when the request's deadline would pass
+// during the backoff.
+func Retry(next IdempotentHandler) IdempotentHandler {
+	return func(ctx Plugins, meta hexa_inbound.RequestMeta, cmd inbound.IdempotentCommand) (hexa_inbound.Result, error) {
+		r := ctx.Retrier()
+		if r == nil || cmd.IdempotencyKey() == "" {
+			return next(ctx, meta, cmd)
+		}
+		r.Deposit()
+
+		res, err := next(ctx, meta, cmd)
+		for attempt := 1; err != nil && apperr.IsTransient(err); attempt++ {
+			dl, _ := ctx.Deadline()
+			wait, ok := r.Retry(attempt, dl)
+			if !ok {
+				break
+			}
+			t := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return res, err
+			case <-t.C:
+			}
+			res, err = next(ctx, meta, cmd)
+		}
+		return res, err
+	}
+}
@@ -0,0 +1,98 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+	"fintech-capstone/m/v2/internal/retry_budget"
+
+	"github.com/google/uuid"
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// retryPlugins returns plugins with only a retry budget, bound to a request with
+// a deadline.
+func retryPlugins(t *testing.T, budget *retry_budget.Budget) Plugins {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	t.Cleanup(cancel)
+	return NewPluginsImpl(ctx, nil, nil, nil, nil, nil, nil, nil, budget)
+}
+
+// flaky fails with errs in turn, then succeeds, counting its calls.
+func flaky(calls *int, errs ...error) IdempotentHandler {
+	return func(Plugins, hexa_inbound.RequestMeta, inbound.IdempotentCommand) (hexa_inbound.Result, error) {
+		*calls++
+		if *calls <= len(errs) {
+			return nil, errs[*calls-1]
+		}
+		return inbound.NewTransferResult(uuid.New(), hexa_inbound.ResultStatusSuccess, "ok"), nil
+	}
+}
+
+func quickBudget() *retry_budget.Budget {
+	return retry_budget.New(retry_budget.Config{BaseBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+}
+
+func TestRetryRunsTransientFailuresAgain(t *testing.T) {
+	budget := quickBudget()
+	lockTimeout := apperr.Transient("ledger lock wait timed out", context.DeadlineExceeded)
+	calls := 0
+	h := Retry(flaky(&calls, lockTimeout, fmt.Errorf("submit: %w", lockTimeout)))
+
+	res, err := h(retryPlugins(t, budget), hexa_inbound.RequestMeta{}, inbound.NewTransferCommand("a", "b", 100, "k1"))
+	if err != nil || res.Status() != hexa_inbound.ResultStatusSuccess {
+		t.Fatalf("got %v, %v; want success on the third attempt", res, err)
+	}
+	if calls != 3 {
+		t.Fatalf("calls = %d, want 3 (a wrapped transient error is retried too)", calls)
+	}
+	if st := budget.Stats(); st.Retries != 2 {
+		t.Fatalf("retries = %d, want 2", st.Retries)
+	}
+}
+
+func TestRetryLeavesOtherFailuresAlone(t *testing.T) {
+	for name, tc := range map[string]struct {
+		err error
+		key string
+	}{
+		"invalid":     {apperr.Invalid("amount must be positive"), "k1"},
+		"overloaded":  {apperr.Overloaded("dispatcher overloaded"), "k1"},
+		"plain error": {errors.New("boom"), "k1"},
+		"no key":      {apperr.Transient("ledger lock wait timed out", nil), ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			h := Retry(flaky(&calls, tc.err))
+			if _, err := h(retryPlugins(t, quickBudget()), hexa_inbound.RequestMeta{}, inbound.NewTransferCommand("a", "b", 100, tc.key)); err != tc.err {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			if calls != 1 {
+				t.Fatalf("calls = %d, want 1", calls)
+			}
+		})
+	}
+}
+
+func TestRetryStopsWhenTheBudgetRunsOut(t *testing.T) {
+	budget := retry_budget.New(retry_budget.Config{Burst: 1, MinPerSecond: 1e-9, BaseBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+	transient := apperr.Transient("leader changed", nil)
+	calls := 0
+	h := Retry(flaky(&calls, transient, transient, transient))
+
+	if _, err := h(retryPlugins(t, budget), hexa_inbound.RequestMeta{}, inbound.NewTransferCommand("a", "b", 100, "k1")); err != transient {
+		t.Fatalf("err = %v, want the transient failure once the budget is spent", err)
+	}
+	if calls != 2 {
+		t.Fatalf("calls = %d, want 2 (one retry funded by a burst of 1)", calls)
+	}
+	if st := budget.Stats(); st.Denied != 1 {
+		t.Fatalf("denied = %d, want 1", st.Denied)
+	}
+}
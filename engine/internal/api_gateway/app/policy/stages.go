@@ -6,8 +6,13 @@ type PolicyStage int
 const (
 	StageIdempotency PolicyStage = iota
 	StageRateLimit
+	StagePriority
 	StageTimeout
 	StageLatency
+	StageEvents
+	StageDeadLetter
+	StageRetry
+	StageCircuitBreaker
 )
 
 // Policy represents a middleware policy at a specific stage.
@@ -23,9 +28,21 @@ type Policy struct {
 // That reduces client churn and lowers total work across the system.
 // Idempotency lookup is fast & cheap (in-memory) and resilient.
 // Under a thundering herd of retries, this short-circuits work earlier than rate limit does.
+// Priority classification runs after rate limiting so rejected requests never pick a queue.
+// Events and dead-lettering sit innermost so they see the use case outcome before any policy
+// rewrites it; idempotent replays are short-circuited long before, so events fire once.
+// Retry sits inside them, so a transfer is announced or dead-lettered once, after
+// its last attempt. The circuit breaker wraps the dispatcher call directly: it sees
+// every attempt's outcome, and its fast failures are neither dead-lettered,
+// announced nor retried.
 var DefaultPolicyOrder = []PolicyStage{
 	StageIdempotency,
 	StageRateLimit,
+	StagePriority,
 	StageTimeout,
 	StageLatency,
+	StageEvents,
+	StageDeadLetter,
+	StageRetry,
+	StageCircuitBreaker,
 }
@@ -10,7 +10,16 @@ import (
 	"github.com/race-conditioned/hexa/horizon/ports/inbound"
 )
 
-// Timeout is a middleware that enforces a timeout on request processing.
+// ErrRouteTimeout is the cause of a context whose gateway-owned budget expired:
+// the route timeout here, or the async completion timeout. Stages tell it apart
+// from the caller's own deadline with context.Cause.
+var ErrRouteTimeout = apperr.Timeout("processing timeout")
+
+// Timeout is a middleware that bounds request processing by the route timeout,
+// merged with whatever deadline the request already carries (gRPC deadline, client
+// disconnect). The stages below run on the derived context, and it is cancelled as
+// soon as Timeout answers: queued dispatcher jobs are then skipped and running
+// ones see ctx.Done, instead of committing after the caller got a 504.
 func Timeout(next AppHandler) AppHandler {
 	return func(ctx Plugins, meta inbound.RequestMeta, cmd inbound.Command) (inbound.Result, error) {
 		fmt.Println("Applying timeout...")
@@ -19,7 +28,7 @@ func Timeout(next AppHandler) AppHandler {
 		if ctx.Timeout() <= 0 {
 			return next(ctx, meta, cmd)
 		}
-		cctx, cancel := context.WithTimeout(context.Background(), ctx.Timeout()) // TODO: propogate context in horizon.context
+		cctx, cancel := context.WithTimeoutCause(ctx, ctx.Timeout(), ErrRouteTimeout)
 		defer cancel()
 
 		done := make(chan struct {
@@ -28,7 +37,7 @@ func Timeout(next AppHandler) AppHandler {
 		}, 1)
 
 		go func() {
-			r, e := next(ctx, meta, cmd) // TODO: add new ctx, with timeout
+			r, e := next(ctx.WithContext(cctx), meta, cmd)
 			done <- struct {
 				res inbound.Result
 				err error
@@ -37,13 +46,13 @@ func Timeout(next AppHandler) AppHandler {
 
 		select {
 		case <-cctx.Done():
-			// Explicit timeout: propagate a well-defined app error
+			// Explicit timeout (ours or the caller's deadline): a well-defined app error
 			if errors.Is(cctx.Err(), context.DeadlineExceeded) {
 				ctx.Metrics().IncTimeout()
 				return zero, apperr.Timeout("processing timeout")
 			}
-			// Context canceled for another reason (e.g., parent canceled)
-			return zero, apperr.Internal(cctx.Err().Error())
+			// The caller went away (client disconnect, gRPC cancel)
+			return zero, apperr.Internal("request canceled")
 		case out := <-done:
 			// Return successful result if completed before timeout
 			return out.res, out.err
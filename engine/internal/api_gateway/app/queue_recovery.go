@@ -0,0 +1,328 @@
+package app
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/app/policy"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+
+	"github.com/google/uuid"
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+const (
+	// resumeConcurrency bounds how many accounts resume in parallel.
+	resumeConcurrency = 16
+	// checkpointInterval is how often Resume saves the jobs it has not finished.
+	checkpointInterval = 250 * time.Millisecond
+)
+
+// QueueRecovery saves the dispatcher's queued jobs on shutdown and resumes them on
+// the next boot through the transfer policy chain.
+type QueueRecovery struct {
+	dispatcher outbound.Dispatcher
+	journal    outbound.PendingJobs
+	resume     policy.TransferHandler
+	statuses   outbound.TransferStatuses
+	logger     platform.Logger
+}
+
+// NewQueueRecovery creates a new QueueRecovery.
+//
+// resume is the composed handler recovered jobs run through, so they are treated
+// like any transfer: the idempotency stage stores their outcomes (the in-memory
+// store is empty after a restart, so a client retrying the key then gets the
+// resumed outcome), and the events, dead-letter, retry and breaker stages see
+// them. It should leave out the rate limit, which charged each job when it was
+// first submitted. statuses tracks resumed jobs under the transaction ID their
+// callers were given when the queue was drained.
+func NewQueueRecovery(d outbound.Dispatcher, journal outbound.PendingJobs, resume policy.TransferHandler, statuses outbound.TransferStatuses, l platform.Logger) *QueueRecovery {
+	return &QueueRecovery{dispatcher: d, journal: journal, resume: resume, statuses: statuses, logger: l}
+}
+
+// Persist drains the dispatcher and saves the jobs it had not started; their
+// callers are answered that the transfer was accepted. Jobs on a worker get until
+// ctx ends to finish. Jobs left over from an interrupted resume stay ahead of the
+// new ones.
+func (r *QueueRecovery) Persist(ctx context.Context) error {
+	d, ok := r.dispatcher.(outbound.Drainer)
+	if !ok {
+		r.logger.Warn("dispatcher cannot drain; queued transfers are dropped on shutdown")
+		return nil
+	}
+
+	start := time.Now()
+	r.logger.Info("draining dispatcher",
+		platform.Field{Key: "queued", Value: r.dispatcher.QueueDepth()},
+		platform.Field{Key: "running", Value: r.dispatcher.ActiveWorkers()},
+	)
+	pending, drainErr := d.Drain(ctx)
+	if drainErr != nil {
+		r.logger.Warn("dispatcher drain incomplete",
+			platform.Field{Key: "error", Value: drainErr.Error()},
+		)
+	}
+
+	prev, err := r.journal.Load()
+	if err != nil {
+		r.logger.Error(err, platform.Field{Key: "component", Value: "job_journal"})
+	}
+	jobs := append(prev, pending...)
+	for i := range jobs {
+		jobs[i].Seq = uint64(i + 1)
+	}
+	for _, j := range pending {
+		r.logger.Debug("persisting queued transfer",
+			platform.Field{Key: "idempotency_key", Value: j.IdempotencyKey},
+			platform.Field{Key: "from_account", Value: j.FromAccount},
+		)
+	}
+	if err := r.journal.Save(jobs); err != nil {
+		r.logger.Error(err, platform.Field{Key: "component", Value: "job_journal"},
+			platform.Field{Key: "lost", Value: len(jobs)},
+		)
+		return err
+	}
+
+	r.logger.Info("dispatcher drained",
+		platform.Field{Key: "persisted", Value: len(pending)},
+		platform.Field{Key: "carried_over", Value: len(prev)},
+		platform.Field{Key: "elapsed_ms", Value: time.Since(start).Milliseconds()},
+	)
+	return drainErr
+}
+
+// Resume runs the jobs saved by Persist. Call it before serving traffic, so resumed
+// jobs keep their place ahead of new submissions. Jobs of one source account run in
+// order, one at a time; accounts run in parallel. Jobs not attempted before ctx ends
+// are saved back for the next boot.
+//
+// The journal is checkpointed while jobs finish, so a crash during Resume repeats
+// at most the jobs of the last checkpoint interval. Those run again under the same
+// transaction ID, which the executor uses to recognise them.
+func (r *QueueRecovery) Resume(ctx policy.Plugins) error {
+	jobs, err := r.journal.Load()
+	if err != nil {
+		return err
+	}
+	if len(jobs) == 0 {
+		return nil
+	}
+	start := time.Now()
+	r.logger.Info("resuming queued transfers", platform.Field{Key: "count", Value: len(jobs)})
+
+	// Group by account, keeping submission order inside and across groups.
+	// Polls see every saved job as pending until it runs.
+	for i := range jobs {
+		if jobs[i].TransactionID == uuid.Nil {
+			jobs[i].TransactionID = uuid.New() // saved before drains assigned one
+		}
+	}
+	var order []string
+	groups := make(map[string][]outbound.PendingJob)
+	left := newCheckpoint(jobs)
+	for _, j := range jobs {
+		if _, ok := groups[j.FromAccount]; !ok {
+			order = append(order, j.FromAccount)
+		}
+		groups[j.FromAccount] = append(groups[j.FromAccount], j)
+		r.statuses.Put(outbound.TransferStatus{
+			TransactionID: j.TransactionID,
+			ClientID:      j.ClientID,
+			State:         outbound.TransferPending,
+			AcceptedAt:    j.QueuedAt,
+		})
+	}
+
+	stopCheckpoints := make(chan struct{})
+	checkpoints := make(chan struct{})
+	go func() {
+		defer close(checkpoints)
+		t := time.NewTicker(checkpointInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				r.checkpoint(left)
+			case <-stopCheckpoints:
+				return
+			}
+		}
+	}()
+
+	var (
+		resumed, failed, parked atomic.Int64
+		wg                      sync.WaitGroup
+	)
+	sem := make(chan struct{}, resumeConcurrency)
+	for _, account := range order {
+		group := groups[account]
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			for _, j := range group {
+				if ctx.Err() != nil {
+					return
+				}
+				switch r.resumeOne(ctx, j) {
+				case resumeDone:
+					resumed.Add(1)
+				case resumeFailed:
+					failed.Add(1)
+				case resumeDeadLettered:
+					parked.Add(1)
+				}
+				left.done(j.Seq)
+			}
+		}()
+	}
+	wg.Wait()
+	close(stopCheckpoints)
+	<-checkpoints
+
+	leftover := left.jobs()
+	if err := r.journal.Save(leftover); err != nil {
+		return err
+	}
+	r.logger.Info("queued transfers resumed",
+		platform.Field{Key: "resumed", Value: resumed.Load()},
+		platform.Field{Key: "failed", Value: failed.Load()},
+		platform.Field{Key: "dead_lettered", Value: parked.Load()},
+		platform.Field{Key: "deferred", Value: len(leftover)},
+		platform.Field{Key: "elapsed_ms", Value: time.Since(start).Milliseconds()},
+	)
+	return nil
+}
+
+// checkpoint saves the jobs not finished yet, if any finished since the last save.
+func (r *QueueRecovery) checkpoint(left *checkpoint) {
+	jobs, changed := left.take()
+	if !changed {
+		return
+	}
+	if err := r.journal.Save(jobs); err != nil {
+		r.logger.Error(err, platform.Field{Key: "component", Value: "job_journal"})
+	}
+}
+
+type resumeOutcome int
+
+const (
+	resumeDone         resumeOutcome = iota
+	resumeFailed                     // a final answer, e.g. insufficient funds
+	resumeDeadLettered               // parked for an admin to replay or discard
+)
+
+// resumeOne runs a single saved job through the policy chain and records its
+// outcome for status polls. Nobody waits for the answer any more, so a failure
+// that is not final is parked in the dead-letter store rather than dropped; the
+// dead-letter stage has already parked internal errors.
+//
+// The job runs as its client, on no route: the idempotency store's default
+// retention applies to its outcome.
+func (r *QueueRecovery) resumeOne(ctx policy.Plugins, j outbound.PendingJob) resumeOutcome {
+	meta := hexa_inbound.RequestMeta{ClientID: j.ClientID, Protocol: "resume"}
+	cmd := j.Command()
+	res, err := r.resume(ctx.WithContext(j.Context(ctx)), meta, cmd)
+
+	st := outbound.TransferStatus{
+		TransactionID: j.TransactionID,
+		ClientID:      j.ClientID,
+		AcceptedAt:    j.QueuedAt,
+		CompletedAt:   time.Now().UTC(),
+	}
+	if err == nil {
+		st.State = outbound.TransferCompleted
+		st.Result = res
+		r.statuses.Put(st)
+		r.logger.Debug("queued transfer resumed",
+			platform.Field{Key: "idempotency_key", Value: j.IdempotencyKey},
+			platform.Field{Key: "transaction_id", Value: res.TransactionID().String()},
+		)
+		return resumeDone
+	}
+
+	st.State = outbound.TransferFailed
+	st.Error = apperr.As(err).Msg
+	r.statuses.Put(st)
+	r.logger.Warn("queued transfer failed to resume",
+		platform.Field{Key: "idempotency_key", Value: j.IdempotencyKey},
+		platform.Field{Key: "error", Value: err.Error()},
+	)
+	if apperr.IsTerminal(err) || ctx.DeadLetters() == nil {
+		return resumeFailed
+	}
+	if apperr.As(err).Code == apperr.CodeInternal {
+		return resumeDeadLettered // parked by the dead-letter stage
+	}
+	if cerr := ctx.DeadLetters().Capture(meta, cmd, err); cerr != nil {
+		ctx.Metrics().IncDeadLetterFailure()
+		r.logger.Error(cerr, platform.Field{Key: "idempotency_key", Value: j.IdempotencyKey})
+		return resumeFailed
+	}
+	ctx.Metrics().IncDeadLettered()
+	return resumeDeadLettered
+}
+
+// checkpoint tracks the saved jobs Resume has not finished. It is safe for
+// concurrent use.
+type checkpoint struct {
+	mu      sync.Mutex
+	left    map[uint64]outbound.PendingJob // by Seq
+	changed bool
+}
+
+func newCheckpoint(jobs []outbound.PendingJob) *checkpoint {
+	c := &checkpoint{left: make(map[uint64]outbound.PendingJob, len(jobs))}
+	for _, j := range jobs {
+		c.left[j.Seq] = j
+	}
+	return c
+}
+
+// done marks the job with seq finished.
+func (c *checkpoint) done(seq uint64) {
+	c.mu.Lock()
+	delete(c.left, seq)
+	c.changed = true
+	c.mu.Unlock()
+}
+
+// take returns the jobs left, in submission order, and whether any finished since
+// the last take.
+func (c *checkpoint) take() ([]outbound.PendingJob, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	changed := c.changed
+	c.changed = false
+	return c.sorted(), changed
+}
+
+// jobs returns the jobs left, in submission order.
+func (c *checkpoint) jobs() []outbound.PendingJob {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sorted()
+}
+
+// sorted returns the jobs left by Seq. Caller must hold c.mu.
+func (c *checkpoint) sorted() []outbound.PendingJob {
+	out := make([]outbound.PendingJob, 0, len(c.left))
+	for _, j := range c.left {
+		out = append(out, j)
+	}
+	sort.Slice(out, func(i, k int) bool { return out[i].Seq < out[k].Seq })
+	return out
+}
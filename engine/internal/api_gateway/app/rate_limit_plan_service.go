@@ -0,0 +1,53 @@
+package app
+
+import (
+	"fintech-capstone/m/v2/internal/api_gateway/app/policy"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// RateLimitPlanService reports the rate-limit plan in effect for a client.
+type RateLimitPlanService struct {
+	limiter outbound.PlanLimiter
+	admins  outbound.Admins
+}
+
+// NewRateLimitPlanService creates a new RateLimitPlanService. admins may look up
+// other clients' plans; nil => nobody may.
+func NewRateLimitPlanService(limiter outbound.PlanLimiter, admins outbound.Admins) *RateLimitPlanService {
+	return &RateLimitPlanService{limiter: limiter, admins: admins}
+}
+
+// Plan is a usecase that returns the plan of the calling client
+// (RequestMeta.ClientID). Admins may ask for another client's plan; for anyone
+// else the requested client is ignored.
+func (s *RateLimitPlanService) Plan(_ policy.Plugins, meta hexa_inbound.RequestMeta, cmd inbound.RateLimitPlanCommand) (inbound.RateLimitPlanResult, error) {
+	clientID := meta.ClientID
+	if other := cmd.ClientID(); other != "" && isAdmin(s.admins, meta.ClientID) {
+		clientID = other
+	}
+	if clientID == "" {
+		return inbound.RateLimitPlanResult{}, apperr.Invalid("missing client id")
+	}
+	p := s.limiter.PlanFor(clientID)
+	return inbound.NewRateLimitPlanResult(hexa_inbound.ResultStatusSuccess, "ok", inbound.RateLimitPlanView{
+		ClientID:   p.ClientID,
+		Plan:       p.Plan,
+		Assigned:   p.Assigned,
+		RatePerSec: p.RatePerSec,
+		Burst:      p.Burst,
+		TTL:        p.TTL.String(),
+		Tracked:    p.Tracked,
+		Remaining:  p.Remaining,
+	}), nil
+}
+
+// isAdmin reports whether clientID (the authenticated RequestMeta.ClientID) is
+// one of admins: operators and housekeeping jobs, which may act on other
+// clients' state.
+func isAdmin(admins outbound.Admins, clientID string) bool {
+	return clientID != "" && admins != nil && admins.IsAdmin(clientID)
+}
@@ -32,7 +32,7 @@ func (s *TransferService) SubmitTransfer(ctx policy.Plugins, cmd inbound.Transfe
 		return inbound.TransferResult{}, apperr.Invalid(err.Error())
 	}
 	// Delegate to worker pool via outbound port (transport-agnostic).
-	return s.dispatcher.Submit(ctx, cmd), nil
+	return s.dispatcher.Submit(ctx, cmd)
 }
 
 // validate checks the transfer command for required fields.
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"fintech-capstone/m/v2/internal/api_gateway/app/policy"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// WebhookService lets clients manage their webhook subscriptions and read the
+// delivery log. Every operation is scoped to the calling client (RequestMeta.ClientID).
+type WebhookService struct {
+	hooks  outbound.Webhooks
+	logger platform.Logger
+}
+
+// NewWebhookService creates a new WebhookService.
+func NewWebhookService(hooks outbound.Webhooks, l platform.Logger) *WebhookService {
+	return &WebhookService{hooks: hooks, logger: l}
+}
+
+// Subscribe registers an endpoint. The signing secret is only returned here.
+func (s *WebhookService) Subscribe(_ policy.Plugins, meta hexa_inbound.RequestMeta, cmd inbound.WebhookSubscribeCommand) (inbound.WebhookResult, error) {
+	if meta.ClientID == "" {
+		return inbound.WebhookResult{}, apperr.Invalid("missing client id")
+	}
+	types := make([]outbound.EventType, len(cmd.EventTypes()))
+	for i, t := range cmd.EventTypes() {
+		types[i] = outbound.EventType(t)
+	}
+	sub, err := s.hooks.Subscribe(meta.ClientID, cmd.URL(), types)
+	if err != nil {
+		return inbound.WebhookResult{}, err
+	}
+	s.logger.Info("webhook subscribed",
+		platform.Field{Key: "client_id", Value: meta.ClientID},
+		platform.Field{Key: "subscription_id", Value: sub.ID},
+		platform.Field{Key: "url", Value: sub.URL},
+	)
+	view := subscriptionView(sub)
+	view.Secret = sub.Secret
+	return inbound.NewWebhookResult("subscribed", []inbound.WebhookSubscriptionView{view}, nil), nil
+}
+
+// Unsubscribe removes an endpoint and drops its pending deliveries.
+func (s *WebhookService) Unsubscribe(_ policy.Plugins, meta hexa_inbound.RequestMeta, cmd inbound.WebhookCommand) (inbound.WebhookResult, error) {
+	ok, err := s.hooks.Unsubscribe(meta.ClientID, cmd.ID())
+	if err != nil {
+		return inbound.WebhookResult{}, apperr.Wrap(apperr.CodeInternal, "unsubscribe webhook", err)
+	}
+	if !ok {
+		return inbound.WebhookResult{}, apperr.NotFound("webhook subscription not found")
+	}
+	return inbound.NewWebhookResult("unsubscribed", nil, nil), nil
+}
+
+// Enable re-enables an endpoint disabled after repeated failures.
+func (s *WebhookService) Enable(_ policy.Plugins, meta hexa_inbound.RequestMeta, cmd inbound.WebhookCommand) (inbound.WebhookResult, error) {
+	ok, err := s.hooks.Enable(meta.ClientID, cmd.ID())
+	if err != nil {
+		return inbound.WebhookResult{}, apperr.Wrap(apperr.CodeInternal, "enable webhook", err)
+	}
+	if !ok {
+		return inbound.WebhookResult{}, apperr.NotFound("webhook subscription not found")
+	}
+	return inbound.NewWebhookResult("enabled", nil, nil), nil
+}
+
+// List returns the calling client's subscriptions (without secrets).
+func (s *WebhookService) List(_ policy.Plugins, meta hexa_inbound.RequestMeta, _ inbound.WebhookCommand) (inbound.WebhookResult, error) {
+	subs := s.hooks.Subscriptions(meta.ClientID)
+	views := make([]inbound.WebhookSubscriptionView, len(subs))
+	for i, sub := range subs {
+		views[i] = subscriptionView(sub)
+	}
+	return inbound.NewWebhookResult("ok", views, nil), nil
+}
+
+// Deliveries returns the calling client's delivery log, newest first, optionally
+// narrowed to one subscription.
+func (s *WebhookService) Deliveries(_ policy.Plugins, meta hexa_inbound.RequestMeta, cmd inbound.WebhookCommand) (inbound.WebhookResult, error) {
+	log := s.hooks.Deliveries(meta.ClientID, cmd.ID(), cmd.Limit())
+	views := make([]inbound.WebhookDeliveryView, len(log))
+	for i, a := range log {
+		views[i] = inbound.WebhookDeliveryView{
+			DeliveryID:     a.DeliveryID,
+			SubscriptionID: a.SubscriptionID,
+			EventID:        a.EventID,
+			EventType:      a.EventType.String(),
+			Attempt:        a.Attempt,
+			StatusCode:     a.StatusCode,
+			Error:          a.Error,
+			Outcome:        a.Outcome,
+			LatencyMs:      a.LatencyMs,
+			At:             a.At,
+		}
+	}
+	return inbound.NewWebhookResult("ok", nil, views), nil
+}
+
+// subscriptionView maps a subscription to its external view, without the secret.
+func subscriptionView(sub outbound.WebhookSubscription) inbound.WebhookSubscriptionView {
+	types := make([]string, len(sub.EventTypes))
+	for i, t := range sub.EventTypes {
+		types[i] = t.String()
+	}
+	return inbound.WebhookSubscriptionView{
+		ID:                  sub.ID,
+		URL:                 sub.URL,
+		EventTypes:          types,
+		Disabled:            sub.Disabled,
+		DisabledReason:      sub.DisabledReason,
+		ConsecutiveFailures: sub.ConsecutiveFailures,
+		CreatedAt:           sub.CreatedAt,
+	}
+}
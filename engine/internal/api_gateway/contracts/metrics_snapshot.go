@@ -1,5 +1,11 @@
 package contracts
 
+import (
+	"time"
+
+	"fintech-capstone/m/v2/internal/platform/concurrency"
+)
+
 // MetricsSnapshot defines JSON output for /metrics.
 type MetricsSnapshot struct {
 	RequestsTotal int64   `json:"requests_total"`
@@ -7,4 +13,113 @@ type MetricsSnapshot struct {
 	AvgLatencyMs  float64 `json:"avg_latency_ms"`
 	ActiveWorkers int64   `json:"active_workers"`
 	QueueDepth    int64   `json:"queue_depth"`
+	// QueueDepthByClass breaks QueueDepth down by dispatcher priority class.
+	QueueDepthByClass map[string]int64 `json:"queue_depth_by_class,omitempty"`
+	// Bulkheads reports each isolated worker pool by segment name.
+	Bulkheads map[string]BulkheadSnapshot `json:"bulkheads,omitempty"`
+	// Concurrency reports the adaptive ingress concurrency limit.
+	Concurrency *ConcurrencySnapshot `json:"concurrency,omitempty"`
+}
+
+// BulkheadSnapshot defines JSON output for one dispatcher bulkhead.
+type BulkheadSnapshot struct {
+	Workers       int   `json:"workers"`
+	ActiveWorkers int64 `json:"active_workers"`
+	QueueDepth    int64 `json:"queue_depth"`
+	QueueCapacity int64 `json:"queue_capacity"`
+	Rejected      int64 `json:"rejected"`
+	Shed          int64 `json:"shed"` // dropped from the queue under overload
+}
+
+// ConcurrencySnapshot defines JSON output for the adaptive concurrency limit.
+type ConcurrencySnapshot struct {
+	Algorithm     string  `json:"algorithm"`
+	Limit         int     `json:"limit"`
+	InFlight      int     `json:"in_flight"`
+	RTTMs         float64 `json:"rtt_ms"`
+	BaselineRTTMs float64 `json:"baseline_rtt_ms"`
+	Rejected      int64   `json:"rejected"` // would-be rejections when shadow is set
+	Shadow        bool    `json:"shadow"`
+}
+
+// NewConcurrencySnapshot converts limiter stats to their JSON form.
+func NewConcurrencySnapshot(st concurrency.Stats) *ConcurrencySnapshot {
+	return &ConcurrencySnapshot{
+		Algorithm:     string(st.Algorithm),
+		Limit:         st.Limit,
+		InFlight:      st.InFlight,
+		RTTMs:         float64(st.RTT) / float64(time.Millisecond),
+		BaselineRTTMs: float64(st.BaselineRTT) / float64(time.Millisecond),
+		Rejected:      st.Rejected,
+		Shadow:        st.Shadow,
+	}
+}
+
+// BreakerSnapshot defines JSON output for one circuit breaker.
+type BreakerSnapshot struct {
+	State    string    `json:"state"` // closed, open or half_open
+	Since    time.Time `json:"since"` // last transition
+	Requests int       `json:"requests"`
+	Failures int       `json:"failures"`
+	Opened   int64     `json:"opened"`   // transitions to open
+	Rejected int64     `json:"rejected"` // calls failed fast
+}
+
+// RetrySnapshot defines JSON output for the server-side retry budget.
+type RetrySnapshot struct {
+	Tokens  float64 `json:"tokens"`  // retries the budget can still pay for
+	Retries int64   `json:"retries"` // retries granted
+	Denied  int64   `json:"denied"`  // retries refused by the budget
+}
+
+// IdempotencySnapshot defines JSON output for the idempotency store.
+type IdempotencySnapshot struct {
+	Entries       int   `json:"entries"`
+	Bytes         int64 `json:"bytes"` // approximate
+	Hits          int64 `json:"hits"`
+	Misses        int64 `json:"misses"`
+	Evictions     int64 `json:"evictions"`      // dropped by the size bound before expiring
+	Expirations   int64 `json:"expirations"`    // dropped after their retention window
+	InFlight      int   `json:"in_flight"`      // keys claimed by a running request
+	Contended     int64 `json:"contended"`      // duplicates that found their key in flight
+	ClaimsExpired int64 `json:"claims_expired"` // claims never released by their owner
+}
+
+// LimiterSnapshot defines JSON output for the rate limiter's configuration and
+// per-route cost accounting.
+type LimiterSnapshot struct {
+	PerClientRate  float64                      `json:"per_client_rate"`
+	PerClientBurst int                          `json:"per_client_burst"`
+	GlobalRate     float64                      `json:"global_rate"` // 0 => no global bucket
+	GlobalBurst    int                          `json:"global_burst"`
+	Routes         map[string]RouteCostSnapshot `json:"routes"`
+	DefaultPlan    string                       `json:"default_plan"` // per_client_* are this plan's
+	Plans          map[string]PlanSnapshot      `json:"plans"`
+	AssignedPlans  int                          `json:"assigned_plans"` // clients with an explicit plan
+	// Distributed is set when limits are shared across replicas through a store.
+	Distributed *DistributedLimiterSnapshot `json:"distributed,omitempty"`
+}
+
+// DistributedLimiterSnapshot defines JSON output for shared (cross-replica) limiting.
+type DistributedLimiterSnapshot struct {
+	Decisions   int64 `json:"decisions"`
+	Fallbacks   int64 `json:"fallbacks"`    // decided locally: store failing or breaker open
+	StoreErrors int64 `json:"store_errors"` // store calls that failed
+	Conflicts   int64 `json:"conflicts"`    // lost compare-and-swaps, retried
+}
+
+// PlanSnapshot defines JSON output for one per-client rate-limit plan.
+type PlanSnapshot struct {
+	RatePerSec float64 `json:"rate_per_sec"` // <= 0 => unlimited
+	Burst      int     `json:"burst"`
+	TTL        string  `json:"ttl"` // idle time before a client's bucket is dropped
+}
+
+// RouteCostSnapshot defines JSON output for one priced route.
+type RouteCostSnapshot struct {
+	Base     float64 `json:"base"`     // tokens every request pays
+	MaxCost  float64 `json:"max_cost"` // base plus the dearest amount band
+	Requests int64   `json:"requests"`
+	Refused  int64   `json:"refused"`
+	Charged  float64 `json:"charged"` // tokens consumed by admitted requests
 }
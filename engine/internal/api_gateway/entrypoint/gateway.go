@@ -4,11 +4,13 @@ import (
 	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
 	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
 	"fintech-capstone/m/v2/internal/platform"
+	"fintech-capstone/m/v2/internal/platform/concurrency"
 )
 
 // Gateway is the API Gateway entrypoint, composing handlers with middleware.
 type Gateway struct {
 	transferH  inbound.UnaryHandler[inbound.TransferCommand, inbound.TransferResult]
+	limiter    *concurrency.Limiter
 	metrics    outbound.Metrics
 	dispatcher outbound.Dispatcher
 	logger     platform.Logger
@@ -22,6 +24,16 @@ func WithTransfer(h inbound.UnaryHandler[inbound.TransferCommand, inbound.Transf
 	return func(g *Gateway) { g.transferH = h }
 }
 
+// WithConcurrencyLimiter sets the adaptive ingress limit.
+func WithConcurrencyLimiter(l *concurrency.Limiter) Option {
+	return func(g *Gateway) { g.limiter = l }
+}
+
+// ConcurrencyLimiter returns the adaptive ingress limit, or nil if it is not enabled.
+func (g *Gateway) ConcurrencyLimiter() *concurrency.Limiter {
+	return g.limiter
+}
+
 // NewGateway constructs a new API Gateway entrypoint with all handlers composed with middleware.
 func NewGateway(
 	metrics outbound.Metrics,
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fintech-capstone/m/v2/internal/api_gateway/contracts"
 	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
 )
 
 // MetricsHandler handles metrics requests.
@@ -11,5 +12,17 @@ func (g *Gateway) MetricsHandler(ctx context.Context, _ inbound.RequestMeta, _ s
 	s := g.metrics.Snapshot()
 	s.ActiveWorkers = g.dispatcher.ActiveWorkers()
 	s.QueueDepth = g.dispatcher.QueueDepth()
+	if byClass := g.dispatcher.QueueDepthByClass(); len(byClass) > 0 {
+		s.QueueDepthByClass = make(map[string]int64, len(byClass))
+		for class, depth := range byClass {
+			s.QueueDepthByClass[class.String()] = depth
+		}
+	}
+	if br, ok := g.dispatcher.(outbound.BulkheadReporter); ok {
+		s.Bulkheads = br.Bulkheads()
+	}
+	if g.limiter != nil {
+		s.Concurrency = contracts.NewConcurrencySnapshot(g.limiter.Stats())
+	}
 	return s, nil
 }
@@ -16,3 +16,10 @@ type IdempotentCommand interface {
 	inbound.Command
 	Idempotent
 }
+
+// Fingerprinted is an optional Command Capability: a canonical hash of the fields
+// that define the request, so that reusing an idempotency key with a different
+// payload can be told apart from a retry.
+type Fingerprinted interface {
+	Fingerprint() string
+}
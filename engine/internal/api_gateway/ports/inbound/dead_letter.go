@@ -0,0 +1,98 @@
+package inbound
+
+import (
+	"time"
+
+	"github.com/race-conditioned/hexa/horizon/ports/inbound"
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// DeadLetterCommandHTTP defines the HTTP API payload for the dead-letter inspect,
+// replay and discard endpoints.
+type DeadLetterCommandHTTP struct {
+	ID string `json:"id"`
+}
+
+func (dto *DeadLetterCommandHTTP) ToCommand() inbound.Command {
+	return DeadLetterCommand{id: dto.ID}
+}
+
+// DeadLetterCommand addresses one dead-lettered transfer from any transport.
+type DeadLetterCommand struct {
+	id string
+}
+
+// NewDeadLetterCommand creates a new DeadLetterCommand.
+func NewDeadLetterCommand(id string) DeadLetterCommand {
+	return DeadLetterCommand{id: id}
+}
+
+// ID returns the dead letter ID (the client-scoped idempotency key of the failed transfer).
+func (c DeadLetterCommand) ID() string { return c.id }
+
+// DeadLetterListCommandHTTP defines the HTTP API payload for the dead-letter list endpoint.
+type DeadLetterListCommandHTTP struct{}
+
+func (dto *DeadLetterListCommandHTTP) ToCommand() inbound.Command {
+	return DeadLetterListCommand{}
+}
+
+// DeadLetterListCommand lists all dead-lettered transfers.
+type DeadLetterListCommand struct{}
+
+// DeadLetterView is the external view of a dead-lettered transfer.
+type DeadLetterView struct {
+	ID             string    `json:"id"`
+	IdempotencyKey string    `json:"idempotency_key"`
+	FromAccount    string    `json:"from_account"`
+	ToAccount      string    `json:"to_account"`
+	AmountCents    int64     `json:"amount_cents"`
+	ClientID       string    `json:"client_id"`
+	RequestID      string    `json:"request_id"`
+	TraceID        string    `json:"trace_id"`
+	Error          string    `json:"error"`
+	Attempts       int       `json:"attempts"`
+	FirstFailedAt  time.Time `json:"first_failed_at"`
+	LastFailedAt   time.Time `json:"last_failed_at"`
+}
+
+// DeadLetterResult is returned by the dead-letter admin use cases.
+type DeadLetterResult struct {
+	status  hexa_inbound.ResultStatus
+	message string
+	entries []DeadLetterView
+}
+
+// NewDeadLetterResult creates a new DeadLetterResult.
+func NewDeadLetterResult(status hexa_inbound.ResultStatus, message string, entries []DeadLetterView) DeadLetterResult {
+	return DeadLetterResult{status: status, message: message, entries: entries}
+}
+
+// Status returns the status of the admin operation.
+func (r DeadLetterResult) Status() hexa_inbound.ResultStatus { return r.status }
+
+// Message returns the message associated with the result.
+func (r DeadLetterResult) Message() string { return r.message }
+
+// Entries returns the dead letters the operation returned.
+func (r DeadLetterResult) Entries() []DeadLetterView { return r.entries }
+
+func (r DeadLetterResult) Encode(s inbound.Sink) {
+	entries := r.entries
+	if entries == nil {
+		entries = []DeadLetterView{}
+	}
+	s.Write(r.status.String(), DeadLetterResponse{
+		Status:  r.status.String(),
+		Message: r.message,
+		Count:   len(entries),
+		Entries: entries,
+	})
+}
+
+type DeadLetterResponse struct {
+	Status  string           `json:"status"`
+	Message string           `json:"message"`
+	Count   int              `json:"count"`
+	Entries []DeadLetterView `json:"entries"`
+}
@@ -0,0 +1,55 @@
+package inbound
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// Transport names for idempotency keys and replay markers.
+const (
+	// IdempotencyKeyHeader carries the key per the IETF httpapi idempotency-key
+	// draft, as a structured-field string: Idempotency-Key: "k-123".
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotencyKeyMetadata is the gRPC metadata equivalent.
+	IdempotencyKeyMetadata = "idempotency-key"
+	// IdempotentReplayedHeader ("true") marks an HTTP response replayed from the
+	// idempotency store; IdempotentReplayedTrailer is the gRPC trailer.
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	IdempotentReplayedTrailer = "idempotent-replayed"
+)
+
+// Replayable is an optional Result Capability: the copy returned to a duplicate
+// request, with status hexa_inbound.ResultStatusDuplicate.
+type Replayable interface {
+	Replay() hexa_inbound.Result
+}
+
+// Replayed reports whether res was answered from the idempotency store.
+func Replayed(res hexa_inbound.Result) bool {
+	return res != nil && res.Status() == hexa_inbound.ResultStatusDuplicate
+}
+
+// ResolveIdempotencyKey merges an Idempotency-Key header (or metadata) value with
+// the key in the payload. The header is an sf-string ("k-123"); a bare token is
+// accepted too. When both are set they must be equal.
+func ResolveIdempotencyKey(header, payload string) (string, error) {
+	header = strings.TrimSpace(header)
+	if strings.HasPrefix(header, `"`) {
+		k, err := strconv.Unquote(header)
+		if err != nil || k == "" {
+			return "", errors.New("malformed Idempotency-Key header")
+		}
+		header = k
+	}
+	switch {
+	case header == "":
+		return payload, nil
+	case payload == "" || payload == header:
+		return header, nil
+	default:
+		return "", errors.New("Idempotency-Key header does not match idempotency_key")
+	}
+}
@@ -17,3 +17,10 @@ type RequestMeta struct {
 	Protocol  NetComProtocol
 	Target    string // path or method name for logging
 }
+
+// Transport names for the dispatcher priority class a client asks for
+// (outbound.WithRequestedPriority).
+const (
+	PriorityClassHeader   = "X-Priority-Class"
+	PriorityClassMetadata = "x-priority-class"
+)
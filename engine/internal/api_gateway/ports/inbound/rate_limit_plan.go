@@ -0,0 +1,71 @@
+package inbound
+
+import (
+	"github.com/race-conditioned/hexa/horizon/ports/inbound"
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// RateLimitPlanCommandHTTP defines the HTTP API payload for the rate-limit plan endpoint.
+type RateLimitPlanCommandHTTP struct {
+	ClientID string `json:"client_id"` // internal callers only; others always get their own
+}
+
+func (dto *RateLimitPlanCommandHTTP) ToCommand() inbound.Command {
+	return RateLimitPlanCommand{clientID: dto.ClientID}
+}
+
+// RateLimitPlanCommand asks which rate-limit plan is in effect for a client.
+type RateLimitPlanCommand struct {
+	clientID string
+}
+
+// NewRateLimitPlanCommand creates a new RateLimitPlanCommand.
+func NewRateLimitPlanCommand(clientID string) RateLimitPlanCommand {
+	return RateLimitPlanCommand{clientID: clientID}
+}
+
+// ClientID returns the client to look up; "" means the calling client.
+func (c RateLimitPlanCommand) ClientID() string { return c.clientID }
+
+// RateLimitPlanView is the external view of a client's rate-limit plan.
+type RateLimitPlanView struct {
+	ClientID   string  `json:"client_id"`
+	Plan       string  `json:"plan"`
+	Assigned   bool    `json:"assigned"` // false => the default plan
+	RatePerSec float64 `json:"rate_per_sec"`
+	Burst      int     `json:"burst"`
+	TTL        string  `json:"ttl"`
+	Tracked    bool    `json:"tracked"`   // the client has a live bucket
+	Remaining  int     `json:"remaining"` // whole tokens left when tracked
+}
+
+// RateLimitPlanResult is returned by the rate-limit plan use case.
+type RateLimitPlanResult struct {
+	status  hexa_inbound.ResultStatus
+	message string
+	plan    RateLimitPlanView
+}
+
+// NewRateLimitPlanResult creates a new RateLimitPlanResult.
+func NewRateLimitPlanResult(status hexa_inbound.ResultStatus, message string, plan RateLimitPlanView) RateLimitPlanResult {
+	return RateLimitPlanResult{status: status, message: message, plan: plan}
+}
+
+// Status returns the status of the lookup.
+func (r RateLimitPlanResult) Status() hexa_inbound.ResultStatus { return r.status }
+
+// Message returns the message associated with the result.
+func (r RateLimitPlanResult) Message() string { return r.message }
+
+// Plan returns the plan in effect.
+func (r RateLimitPlanResult) Plan() RateLimitPlanView { return r.plan }
+
+func (r RateLimitPlanResult) Encode(s inbound.Sink) {
+	s.Write(r.status.String(), RateLimitPlanResponse{Status: r.status.String(), Message: r.message, Plan: r.plan})
+}
+
+type RateLimitPlanResponse struct {
+	Status  string            `json:"status"`
+	Message string            `json:"message"`
+	Plan    RateLimitPlanView `json:"plan"`
+}
@@ -2,6 +2,10 @@ package inbound
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
 
 	"github.com/google/uuid"
 	"github.com/race-conditioned/hexa/horizon/ports/inbound"
@@ -69,12 +73,28 @@ func (t TransferCommand) IdempotencyKey() string {
 	return t.idempotencyKey
 }
 
+// Fingerprint returns a SHA-256 over the versioned, length-prefixed fields that
+// define the transfer (source, destination, amount), excluding the idempotency
+// key. Fields added to TransferCommand (e.g. currency) must be included here.
+func (t TransferCommand) Fingerprint() string {
+	h := sha256.New()
+	for _, f := range []string{"transfer/v1", t.fromAccount, t.toAccount, strconv.FormatInt(t.amountCents, 10)} {
+		fmt.Fprintf(h, "%d:%s;", len(f), f)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ResultStatusAccepted marks a transfer accepted for asynchronous completion.
+// Its outcome is polled through the transfer status endpoint.
+const ResultStatusAccepted hexa_inbound.ResultStatus = "accepted"
+
 // TransferResult returned by the use case.
 // It is the internal representation of a completed transfer job.
 type TransferResult struct {
 	transactionID uuid.UUID
 	status        hexa_inbound.ResultStatus
 	message       string
+	accepted      bool // replay of an accepted result
 }
 
 // NewTransferResult creates a new TransferResult.
@@ -95,12 +115,31 @@ func (t TransferResult) Status() hexa_inbound.ResultStatus { return t.status }
 // Message returns the message associated with the transfer result.
 func (t TransferResult) Message() string { return t.message }
 
+// Accepted reports whether the transfer was accepted for later completion rather
+// than finished, for the result and for a replay of it.
+func (t TransferResult) Accepted() bool {
+	return t.status == ResultStatusAccepted || t.accepted
+}
+
 func (r TransferResult) Encode(s inbound.Sink) {
-	s.Write(r.status.String(), TransferResponse{
+	s.Write(r.status.String(), r.Response())
+}
+
+// Response returns the wire form of the result.
+func (r TransferResult) Response() TransferResponse {
+	return TransferResponse{
 		TransactionID: r.transactionID.String(),
 		Status:        r.status.String(),
 		Message:       r.message,
-	})
+	}
+}
+
+// Replay returns a copy of the result with status duplicate, for a request that
+// reused the idempotency key of a completed transfer.
+func (r TransferResult) Replay() hexa_inbound.Result {
+	r.accepted = r.Accepted()
+	r.status = hexa_inbound.ResultStatusDuplicate
+	return r
 }
 
 type TransferResponse struct {
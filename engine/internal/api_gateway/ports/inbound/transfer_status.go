@@ -0,0 +1,79 @@
+package inbound
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/race-conditioned/hexa/horizon/ports/inbound"
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// TransferStatusCommandHTTP defines the HTTP API payload for the transfer status endpoint.
+type TransferStatusCommandHTTP struct {
+	TransactionID string `json:"transaction_id"`
+}
+
+func (dto *TransferStatusCommandHTTP) ToCommand() inbound.Command {
+	return TransferStatusCommand{transactionID: dto.TransactionID}
+}
+
+// TransferStatusCommand asks for the state of an asynchronously submitted transfer.
+type TransferStatusCommand struct {
+	transactionID string
+}
+
+// NewTransferStatusCommand creates a new TransferStatusCommand.
+func NewTransferStatusCommand(transactionID string) TransferStatusCommand {
+	return TransferStatusCommand{transactionID: transactionID}
+}
+
+// TransactionID returns the transaction ID to look up.
+func (c TransferStatusCommand) TransactionID() string { return c.transactionID }
+
+// TransferStatusResult is returned by the transfer status use case.
+type TransferStatusResult struct {
+	resp TransferStatusResponse
+}
+
+// NewTransferStatusResult creates a new TransferStatusResult.
+func NewTransferStatusResult(resp TransferStatusResponse) TransferStatusResult {
+	return TransferStatusResult{resp: resp}
+}
+
+// Status returns the status of the lookup itself; the transfer's own status is in Response.
+func (r TransferStatusResult) Status() hexa_inbound.ResultStatus {
+	return hexa_inbound.ResultStatusSuccess
+}
+
+// Message returns the lifecycle state of the transfer.
+func (r TransferStatusResult) Message() string { return r.resp.State }
+
+// Response returns the external view of the transfer status.
+func (r TransferStatusResult) Response() TransferStatusResponse { return r.resp }
+
+func (r TransferStatusResult) Encode(s inbound.Sink) {
+	s.Write(hexa_inbound.ResultStatusSuccess.String(), r.resp)
+}
+
+// TransferStatusResponse is the external view of an asynchronously submitted transfer.
+type TransferStatusResponse struct {
+	TransactionID string     `json:"transaction_id"`
+	State         string     `json:"state"`             // pending, completed or failed
+	Status        string     `json:"status,omitempty"`  // transfer result status once completed
+	Message       string     `json:"message,omitempty"` // transfer result message once completed
+	Error         string     `json:"error,omitempty"`   // set when failed
+	AcceptedAt    time.Time  `json:"accepted_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+// TransferAcceptedResponse is returned with 202 Accepted for asynchronous submissions.
+type TransferAcceptedResponse struct {
+	TransactionID string `json:"transaction_id"`
+	Status        string `json:"status"`
+	StatusURL     string `json:"status_url"`
+}
+
+// TransferStatusURL is the polling URL of an asynchronously submitted transfer.
+func TransferStatusURL(id uuid.UUID) string {
+	return "/transfers/" + id.String()
+}
@@ -0,0 +1,123 @@
+package inbound
+
+import (
+	"time"
+
+	"github.com/race-conditioned/hexa/horizon/ports/inbound"
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// WebhookSubscribeCommandHTTP defines the HTTP API payload for /webhooks/subscribe.
+type WebhookSubscribeCommandHTTP struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"` // empty => all events
+}
+
+func (dto *WebhookSubscribeCommandHTTP) ToCommand() inbound.Command {
+	return WebhookSubscribeCommand{url: dto.URL, eventTypes: dto.EventTypes}
+}
+
+// WebhookSubscribeCommand registers a webhook endpoint for the calling client.
+type WebhookSubscribeCommand struct {
+	url        string
+	eventTypes []string
+}
+
+// NewWebhookSubscribeCommand creates a new WebhookSubscribeCommand.
+func NewWebhookSubscribeCommand(url string, eventTypes []string) WebhookSubscribeCommand {
+	return WebhookSubscribeCommand{url: url, eventTypes: eventTypes}
+}
+
+// URL returns the endpoint URL.
+func (c WebhookSubscribeCommand) URL() string { return c.url }
+
+// EventTypes returns the event type filter.
+func (c WebhookSubscribeCommand) EventTypes() []string { return c.eventTypes }
+
+// WebhookCommandHTTP defines the HTTP API payload for the webhook list, unsubscribe,
+// enable and deliveries endpoints.
+type WebhookCommandHTTP struct {
+	ID    string `json:"id,omitempty"`    // subscription ID; optional for list and deliveries
+	Limit int    `json:"limit,omitempty"` // deliveries only
+}
+
+func (dto *WebhookCommandHTTP) ToCommand() inbound.Command {
+	return WebhookCommand{id: dto.ID, limit: dto.Limit}
+}
+
+// WebhookCommand addresses the calling client's webhook subscriptions.
+type WebhookCommand struct {
+	id    string
+	limit int
+}
+
+// NewWebhookCommand creates a new WebhookCommand.
+func NewWebhookCommand(id string, limit int) WebhookCommand {
+	return WebhookCommand{id: id, limit: limit}
+}
+
+// ID returns the subscription ID.
+func (c WebhookCommand) ID() string { return c.id }
+
+// Limit returns the maximum number of log entries to return.
+func (c WebhookCommand) Limit() int { return c.limit }
+
+// WebhookSubscriptionView is the external view of a webhook subscription.
+type WebhookSubscriptionView struct {
+	ID                  string    `json:"id"`
+	URL                 string    `json:"url"`
+	Secret              string    `json:"secret,omitempty"` // only returned on subscribe
+	EventTypes          []string  `json:"event_types"`
+	Disabled            bool      `json:"disabled"`
+	DisabledReason      string    `json:"disabled_reason,omitempty"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+// WebhookDeliveryView is the external view of one webhook delivery attempt.
+type WebhookDeliveryView struct {
+	DeliveryID     string    `json:"delivery_id"`
+	SubscriptionID string    `json:"subscription_id"`
+	EventID        string    `json:"event_id"`
+	EventType      string    `json:"event_type"`
+	Attempt        int       `json:"attempt"`
+	StatusCode     int       `json:"status_code,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	Outcome        string    `json:"outcome"`
+	LatencyMs      int64     `json:"latency_ms"`
+	At             time.Time `json:"at"`
+}
+
+// WebhookResult is returned by the webhook admin use cases.
+type WebhookResult struct {
+	message       string
+	subscriptions []WebhookSubscriptionView
+	deliveries    []WebhookDeliveryView
+}
+
+// NewWebhookResult creates a new WebhookResult.
+func NewWebhookResult(message string, subscriptions []WebhookSubscriptionView, deliveries []WebhookDeliveryView) WebhookResult {
+	return WebhookResult{message: message, subscriptions: subscriptions, deliveries: deliveries}
+}
+
+// Status returns the status of the admin operation.
+func (r WebhookResult) Status() hexa_inbound.ResultStatus { return hexa_inbound.ResultStatusSuccess }
+
+// Message returns the message associated with the result.
+func (r WebhookResult) Message() string { return r.message }
+
+func (r WebhookResult) Encode(s inbound.Sink) {
+	s.Write(hexa_inbound.ResultStatusSuccess.String(), WebhookResponse{
+		Status:        hexa_inbound.ResultStatusSuccess.String(),
+		Message:       r.message,
+		Subscriptions: r.subscriptions,
+		Deliveries:    r.deliveries,
+	})
+}
+
+type WebhookResponse struct {
+	Status        string                    `json:"status"`
+	Message       string                    `json:"message"`
+	Subscriptions []WebhookSubscriptionView `json:"subscriptions,omitempty"`
+	Deliveries    []WebhookDeliveryView     `json:"deliveries,omitempty"`
+}
@@ -0,0 +1,9 @@
+package outbound
+
+// Admins decides which clients may act on other clients' state: their dead
+// letters, rate-limit plans and transfer events. It is an authorization decision,
+// kept apart from the priority tiers: moving a client into the internal tier for
+// throughput grants it nothing here.
+type Admins interface {
+	IsAdmin(clientID string) bool
+}
@@ -0,0 +1,10 @@
+package outbound
+
+// CircuitBreaker guards a downstream dependency (the dispatcher, a ledger client).
+// Allow admits a call, or fails fast with a retryable apperr.Overloaded while the
+// circuit is open. Every admitted call must report its outcome exactly once through
+// done: nil for success, the call's error otherwise. The breaker decides which
+// errors count as failures; caller cancellations and client errors do not.
+type CircuitBreaker interface {
+	Allow() (done func(err error), err error)
+}
@@ -0,0 +1,16 @@
+package outbound
+
+import "time"
+
+// CommitLog is a durable record of committed transfers by client and idempotency
+// key, written where transfers execute. Unlike the idempotency store it survives
+// restarts and outlives the route's retention, so it decides whether a
+// dead-lettered transfer may be replayed.
+type CommitLog interface {
+	// Committed records that the client's transfer with key committed.
+	Committed(clientID, key string) error
+	// Lookup reports whether the client's transfer with key committed. covered is
+	// false when the log does not reach back to since (it was started later or
+	// has pruned that far), so a missing record proves nothing.
+	Lookup(clientID, key string, since time.Time) (committed, covered bool)
+}
@@ -0,0 +1,39 @@
+package outbound
+
+import (
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// DeadLetter is a transfer job that failed for an internal reason (storage error,
+// worker panic...) and is parked until an admin replays or discards it.
+type DeadLetter struct {
+	ID             string                   `json:"id"` // ScopedIdempotencyKey of the failed transfer
+	IdempotencyKey string                   `json:"idempotency_key"`
+	FromAccount    string                   `json:"from_account"`
+	ToAccount      string                   `json:"to_account"`
+	AmountCents    int64                    `json:"amount_cents"`
+	Meta           hexa_inbound.RequestMeta `json:"meta"`
+	Error          string                   `json:"error"`
+	Attempts       int                      `json:"attempts"`
+	FirstFailedAt  time.Time                `json:"first_failed_at"`
+	LastFailedAt   time.Time                `json:"last_failed_at"`
+}
+
+// Command rebuilds the original transfer, including its original idempotency key.
+func (d DeadLetter) Command() inbound.TransferCommand {
+	return inbound.NewTransferCommand(d.FromAccount, d.ToAccount, d.AmountCents, d.IdempotencyKey)
+}
+
+// DeadLetters defines a durable store for failed transfer jobs.
+type DeadLetters interface {
+	// Capture parks a failed transfer; a repeated failure of the same client and key
+	// bumps Attempts.
+	Capture(meta hexa_inbound.RequestMeta, cmd inbound.TransferCommand, cause error) error
+	List() []DeadLetter
+	Get(id string) (DeadLetter, bool)
+	Discard(id string) (bool, error)
+}
@@ -2,12 +2,22 @@ package outbound
 
 import (
 	"context"
+	"fintech-capstone/m/v2/internal/api_gateway/contracts"
 	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
 )
 
 // Dispatcher submits jobs to the worker pool.
+// Submit returns an error when the job could not be queued or was abandoned
+// before a worker produced a result (e.g. queue full, context cancelled).
 type Dispatcher interface {
-	Submit(ctx context.Context, cmd inbound.TransferCommand) inbound.TransferResult
+	Submit(ctx context.Context, cmd inbound.TransferCommand) (inbound.TransferResult, error)
 	QueueDepth() int64
+	QueueDepthByClass() map[PriorityClass]int64
 	ActiveWorkers() int64
 }
+
+// BulkheadReporter is optionally implemented by dispatchers that isolate work
+// into independent pools, keyed by bulkhead (segment) name.
+type BulkheadReporter interface {
+	Bulkheads() map[string]contracts.BulkheadSnapshot
+}
@@ -1,4 +1,10 @@
 // Package outbound declares hexagonal outbound ports the application depends on:
-// Dispatcher (worker pool), Limiter (domain rate limit), Idempotency store, and Metrics.
+// Dispatcher (worker pool), Drainer and PendingJobs (graceful drain and resume),
+// Prioritizer (dispatcher priority classes), CircuitBreaker (fail fast on an
+// unhealthy downstream), Retrier (budgeted retries of transient failures),
+// Limiter (domain rate limit), Idempotency store, DeadLetters (failed job store),
+// CommitLog (committed transfers, consulted before a dead-letter replay),
+// TransferStatuses (async transfer tracking), EventPublisher, Webhooks and
+// EventStream (transfer lifecycle events), and Metrics.
 // Concrete adapters live outside this package.
 package outbound
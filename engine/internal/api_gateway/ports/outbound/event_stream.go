@@ -0,0 +1,62 @@
+package outbound
+
+import "fintech-capstone/m/v2/internal/platform/apperr"
+
+// StreamFilter narrows a live event stream. Empty fields match everything.
+type StreamFilter struct {
+	Account  string // source or destination account
+	ClientID string
+}
+
+// Matches reports whether ev passes the filter.
+func (f StreamFilter) Matches(ev TransferEvent) bool {
+	if f.ClientID != "" && ev.ClientID != f.ClientID {
+		return false
+	}
+	if f.Account != "" && ev.FromAccount != f.Account && ev.ToAccount != f.Account {
+		return false
+	}
+	return true
+}
+
+// ScopeTo checks f against clientID, the authenticated caller. Watchers see only
+// their own events, so f must name clientID; admins (operators, housekeeping) may
+// watch any client or all of them. admins may be nil.
+func (f StreamFilter) ScopeTo(clientID string, admins Admins) error {
+	switch {
+	case clientID == "":
+		return apperr.Invalid("missing client id")
+	case admins != nil && admins.IsAdmin(clientID):
+		return nil
+	case f.ClientID != clientID:
+		return apperr.Invalid("client_id must be the calling client")
+	}
+	return nil
+}
+
+// StreamEvent is a transfer event with its position in the live stream.
+// IDs are opaque to clients; they echo the last one seen to resume.
+type StreamEvent struct {
+	ID    string
+	Event TransferEvent
+}
+
+// EventStream fans transfer events out to live watchers (SSE, gRPC streaming).
+type EventStream interface {
+	// Watch subscribes to events matching f. A non-empty lastEventID resumes after
+	// that event from the replay buffer.
+	Watch(f StreamFilter, lastEventID string) (EventSubscription, error)
+}
+
+// EventSubscription is one live watcher. Its buffer is bounded: when the watcher
+// falls behind, events are dropped and Lagged fires instead of blocking publishers.
+type EventSubscription interface {
+	// Replay returns the buffered events after the resume point, oldest first, and
+	// whether some events since then were already evicted (the client has a gap).
+	Replay() ([]StreamEvent, bool)
+	Events() <-chan StreamEvent
+	// Lagged fires after events were dropped; TakeDropped returns and resets the count.
+	Lagged() <-chan struct{}
+	TakeDropped() uint64
+	Close()
+}
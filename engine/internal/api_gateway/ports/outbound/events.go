@@ -0,0 +1,37 @@
+package outbound
+
+import "time"
+
+// EventType names a transfer lifecycle event.
+type EventType string
+
+const (
+	EventTransferSettled  EventType = "transfer.settled"
+	EventTransferRejected EventType = "transfer.rejected"
+)
+
+// String returns the string representation of the EventType.
+func (e EventType) String() string {
+	return string(e)
+}
+
+// TransferEvent is a transfer lifecycle event pushed to integrators.
+type TransferEvent struct {
+	ID             string    `json:"id"`
+	Type           EventType `json:"type"`
+	ClientID       string    `json:"client_id"`
+	TransactionID  string    `json:"transaction_id,omitempty"`
+	FromAccount    string    `json:"from_account"`
+	ToAccount      string    `json:"to_account"`
+	AmountCents    int64     `json:"amount_cents"`
+	IdempotencyKey string    `json:"idempotency_key"`
+	Status         string    `json:"status"`
+	Message        string    `json:"message,omitempty"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+// EventPublisher accepts transfer lifecycle events for delivery.
+// Publish must not block on delivery itself.
+type EventPublisher interface {
+	Publish(ev TransferEvent) error
+}
@@ -1,7 +1,78 @@
 package outbound
 
+import (
+	"context"
+	"strconv"
+)
+
 // Idempotency defines caching for idempotency keys.
 type Idempotency[T any] interface {
 	Get(key string) (T, bool)
 	Store(key string, res T)
 }
+
+// IdempotencyRecord is what a RecordIdempotency store keeps for a key.
+type IdempotencyRecord[T any] struct {
+	Result T
+	// Fingerprint is a canonical hash of the command that produced Result (see
+	// inbound.Fingerprinted). Empty when unknown: any request may replay it.
+	Fingerprint string
+	// Err is replayed instead of Result for a stored failure. Stores keep only
+	// terminal failures (apperr.IsTerminal) that the route opts into.
+	Err error
+}
+
+// RecordIdempotency is optionally implemented by stores that keep the request
+// fingerprint with each result and whose retention depends on the route
+// (RequestMeta.Target, e.g. "POST /transfer"): payments are kept for a day, while
+// reads that are safe to repeat need not be kept at all.
+type RecordIdempotency[T any] interface {
+	Idempotency[T]
+	GetRecord(key string) (IdempotencyRecord[T], bool)
+	StoreRecord(route, key string, rec IdempotencyRecord[T])
+}
+
+// IdempotencyClaim is the outcome of ClaimIdempotency.Claim: exactly one of Record
+// and Release is set, or neither while another request holds the key.
+type IdempotencyClaim[T any] struct {
+	// Record is the stored result: the key has already been executed.
+	Record *IdempotencyRecord[T]
+	// Release is set when the caller now owns the key. Call it once, after storing
+	// the result (or on failure), so that waiting duplicates proceed.
+	Release func()
+	// Fingerprint and Done describe the holder's claim otherwise: Done is closed
+	// when the holder releases the key or its claim expires.
+	Fingerprint string
+	Done        <-chan struct{}
+}
+
+// ClaimIdempotency is optionally implemented by stores that can mark a key as in
+// progress, so that concurrent duplicates execute once. Claims expire after a
+// store-defined TTL, so a key whose owner crashed does not stay locked.
+type ClaimIdempotency[T any] interface {
+	RecordIdempotency[T]
+	// Claim atomically returns the key's record or, when there is neither a record
+	// nor a live claim, claims the key for the caller.
+	Claim(key, fingerprint string) IdempotencyClaim[T]
+}
+
+// ScopedIdempotencyKey namespaces an idempotency key by client (RequestMeta.ClientID),
+// so two clients sending the same key never share a result. The client ID is length
+// prefixed, so no client can forge another's scope.
+func ScopedIdempotencyKey(clientID, key string) string {
+	return strconv.Itoa(len(clientID)) + ":" + clientID + "/" + key
+}
+
+type clientIDKey struct{}
+
+// WithClientID returns a copy of ctx carrying the calling client's ID, so that
+// dispatchers can persist it with queued jobs.
+func WithClientID(ctx context.Context, clientID string) context.Context {
+	return context.WithValue(ctx, clientIDKey{}, clientID)
+}
+
+// ClientIDFrom retrieves the calling client's ID from the context, if one was set.
+func ClientIDFrom(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(clientIDKey{}).(string)
+	return id, ok && id != ""
+}
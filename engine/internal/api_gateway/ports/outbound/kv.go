@@ -0,0 +1,18 @@
+package outbound
+
+import (
+	"context"
+	"time"
+)
+
+// AtomicKV is a shared key-value store with compare-and-swap and expiry (e.g.
+// Redis, etcd), letting gateway replicas share state such as rate limits.
+// Errors mean the store could not answer (unreachable, timed out); a lost
+// compare-and-swap is not an error.
+type AtomicKV interface {
+	// Get returns the key's value; ok is false when it is missing or expired.
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	// CompareAndSwap stores value with ttl (<= 0 => no expiry) if the key still
+	// holds old, or is missing when old is nil. It reports whether it swapped.
+	CompareAndSwap(ctx context.Context, key string, old, value []byte, ttl time.Duration) (swapped bool, err error)
+}
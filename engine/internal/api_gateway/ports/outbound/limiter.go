@@ -1,6 +1,105 @@
 package outbound
 
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
 // Limiter defines rate limiting behavior.
 type Limiter interface {
-	Allow(clientID string) bool
+	Allow(clientID string) LimitDecision
+}
+
+// CostLimiter is optionally implemented by limiters that charge a request what it
+// costs instead of one token, so a large transfer draws the budget down faster
+// than a cheap read.
+type CostLimiter interface {
+	Limiter
+	// AllowCost charges the cost route (RequestMeta.Target) declares for cmd.
+	AllowCost(clientID, route string, cmd hexa_inbound.Command) LimitDecision
+}
+
+// PlanLimiter is optionally implemented by limiters that apply per-client plans.
+type PlanLimiter interface {
+	Limiter
+	// PlanFor returns the plan in effect for clientID.
+	PlanFor(clientID string) ClientPlan
+}
+
+// ClientPlan is the rate-limit plan in effect for a client.
+type ClientPlan struct {
+	ClientID   string
+	Plan       string
+	Assigned   bool // false => the default plan
+	RatePerSec float64
+	Burst      int
+	TTL        time.Duration
+	// Tracked reports whether the client has a live bucket; Remaining is then
+	// its whole tokens (0 when unlimited).
+	Tracked   bool
+	Remaining int
+}
+
+// LimitDecision is the outcome of Limiter.Allow, detailed enough for clients to
+// pace themselves (Retry-After, RateLimit-* headers, gRPC RetryInfo).
+type LimitDecision struct {
+	Allowed bool
+	// Limit is the burst of the most constraining bucket; 0 when unlimited.
+	Limit int
+	// Remaining is the whole tokens left in that bucket after this call.
+	Remaining int
+	// Reset is the time until that bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until the request would be admitted; 0 when Allowed.
+	RetryAfter time.Duration
+}
+
+// LimitExceeded is the cause of the apperr.RateLimited error for a refused
+// decision, so transports can report the limit alongside Retry-After.
+type LimitExceeded struct {
+	LimitDecision
+}
+
+// Error implements error.
+func (e LimitExceeded) Error() string {
+	return fmt.Sprintf("limit %d, retry in %s", e.Limit, e.RetryAfter.Round(time.Millisecond))
+}
+
+type limitReportKey struct{}
+
+// LimitReport carries the decision that admitted a request from the rate-limit
+// stage back to the transport, which reports it as RateLimit-* headers on success
+// as it does on refusal (there the decision travels in LimitExceeded).
+type LimitReport struct {
+	mu       sync.Mutex
+	decision LimitDecision
+	ok       bool
+}
+
+// WithLimitReport returns a copy of ctx carrying an empty report for the
+// rate-limit stage to fill.
+func WithLimitReport(ctx context.Context) (context.Context, *LimitReport) {
+	r := &LimitReport{}
+	return context.WithValue(ctx, limitReportKey{}, r), r
+}
+
+// ReportLimit records d on the report ctx carries, if any.
+func ReportLimit(ctx context.Context, d LimitDecision) {
+	if r, ok := ctx.Value(limitReportKey{}).(*LimitReport); ok {
+		r.mu.Lock()
+		r.decision, r.ok = d, true
+		r.mu.Unlock()
+	}
+}
+
+// Decision returns the recorded decision, and false when none was (e.g. a replay
+// answered before the rate-limit stage).
+func (r *LimitReport) Decision() (LimitDecision, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.decision, r.ok
 }
@@ -20,6 +20,10 @@ type CounterMetrics interface {
 	IncRateLimited()
 	IncTimeout()
 	IncIdempotentHit()
+	IncDeadLettered()
+	IncDeadLetterFailure()   // the dead-letter store could not park a failed job or drop a settled one
+	IncEventPublishFailure() // a lifecycle event could not be queued for webhook delivery
+	IncCircuitRejected()     // a call failed fast on an open circuit
 }
 
 // LatencyMetrics defines latency observation.
@@ -0,0 +1,61 @@
+package outbound
+
+import (
+	"context"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+
+	"github.com/google/uuid"
+)
+
+// PendingJob is a queued transfer that had not started when the dispatcher was
+// drained. It is persisted on shutdown and resumed on the next boot.
+type PendingJob struct {
+	Seq            uint64        `json:"seq"` // submission order
+	FromAccount    string        `json:"from_account"`
+	ToAccount      string        `json:"to_account"`
+	AmountCents    int64         `json:"amount_cents"`
+	IdempotencyKey string        `json:"idempotency_key"`
+	ClientID       string        `json:"client_id,omitempty"` // scopes the idempotency key
+	Priority       PriorityClass `json:"priority,omitempty"`
+	TransactionID  uuid.UUID     `json:"transaction_id"` // assigned up front (async) or when drained
+	QueuedAt       time.Time     `json:"queued_at"`
+}
+
+// Command rebuilds the original transfer, including its idempotency key.
+func (j PendingJob) Command() inbound.TransferCommand {
+	return inbound.NewTransferCommand(j.FromAccount, j.ToAccount, j.AmountCents, j.IdempotencyKey)
+}
+
+// Context returns a copy of ctx carrying the job's priority class, transaction ID
+// and client ID.
+func (j PendingJob) Context(ctx context.Context) context.Context {
+	if j.ClientID != "" {
+		ctx = WithClientID(ctx, j.ClientID)
+	}
+	if j.Priority != "" {
+		ctx = WithPriority(ctx, j.Priority)
+	}
+	if j.TransactionID != uuid.Nil {
+		ctx = WithTransactionID(ctx, j.TransactionID)
+	}
+	return ctx
+}
+
+// Drainer is optionally implemented by dispatchers that can shut down gracefully.
+type Drainer interface {
+	// Drain stops accepting jobs and waits for running ones until ctx ends. Jobs still
+	// queued are not started: they are returned in submission order, each with a
+	// transaction ID, and their callers get a result with status
+	// inbound.ResultStatusAccepted and that ID, so they poll rather than resubmit.
+	// The error reports jobs still running at the deadline.
+	Drain(ctx context.Context) ([]PendingJob, error)
+}
+
+// PendingJobs persists drained jobs across restarts.
+type PendingJobs interface {
+	// Save replaces the stored jobs; an empty list clears them.
+	Save(jobs []PendingJob) error
+	Load() ([]PendingJob, error)
+}
@@ -0,0 +1,78 @@
+package outbound
+
+import (
+	"context"
+	"strings"
+)
+
+// PriorityClass labels the dispatcher queue a job waits in.
+// Classes are scheduled by weight, so lower classes are slowed down, never starved.
+type PriorityClass string
+
+const (
+	PriorityInteractive PriorityClass = "interactive" // retail, user-facing transfers
+	PriorityBulk        PriorityClass = "bulk"        // batch payouts
+	PriorityInternal    PriorityClass = "internal"    // sweeps and housekeeping
+)
+
+// String returns the string representation of the PriorityClass.
+func (p PriorityClass) String() string {
+	return string(p)
+}
+
+// classOrder lists the classes from the front of the line.
+var classOrder = []PriorityClass{PriorityInteractive, PriorityBulk, PriorityInternal}
+
+// Within reports whether p is a known class no higher than limit, i.e. one a
+// client whose tier is limit may ask for: clients can lower their priority, never
+// raise it.
+func (p PriorityClass) Within(limit PriorityClass) bool {
+	rp, rl := p.rank(), limit.rank()
+	return rp >= 0 && rl >= 0 && rp >= rl
+}
+
+// rank is p's position in classOrder, or -1 for an unknown class.
+func (p PriorityClass) rank() int {
+	for i, c := range classOrder {
+		if c == p {
+			return i
+		}
+	}
+	return -1
+}
+
+// Prioritizer maps a client to its priority class (e.g. via configured tiers).
+type Prioritizer interface {
+	Classify(clientID string) PriorityClass
+}
+
+type priorityKey struct{}
+
+// WithPriority returns a copy of ctx carrying the priority class for the dispatcher.
+func WithPriority(ctx context.Context, p PriorityClass) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFrom retrieves the priority class from the context, if one was set.
+func PriorityFrom(ctx context.Context) (PriorityClass, bool) {
+	p, ok := ctx.Value(priorityKey{}).(PriorityClass)
+	return p, ok && p != ""
+}
+
+type requestedPriorityKey struct{}
+
+// WithRequestedPriority returns a copy of ctx carrying the class the client asked
+// for (X-Priority-Class, x-priority-class metadata). Unlike WithPriority it is
+// only a request: the priority stage grants it within the client's tier.
+func WithRequestedPriority(ctx context.Context, p string) context.Context {
+	if p = strings.TrimSpace(p); p == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestedPriorityKey{}, PriorityClass(p))
+}
+
+// RequestedPriorityFrom retrieves the class the client asked for, if any.
+func RequestedPriorityFrom(ctx context.Context) (PriorityClass, bool) {
+	p, ok := ctx.Value(requestedPriorityKey{}).(PriorityClass)
+	return p, ok
+}
@@ -0,0 +1,14 @@
+package outbound
+
+import "time"
+
+// Retrier decides whether the retry stage may retry a transient failure. It caps
+// retries with a budget earned by first attempts, so retries never amplify an outage.
+type Retrier interface {
+	// Deposit credits the budget for one first attempt.
+	Deposit()
+	// Retry reports whether retry number attempt (1-based) may go ahead and how long
+	// to back off first. It says no without spending when the backoff would run past
+	// deadline (zero => none), and spends from the budget when it says yes.
+	Retry(attempt int, deadline time.Time) (wait time.Duration, ok bool)
+}
@@ -0,0 +1,55 @@
+package outbound
+
+import (
+	"context"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+
+	"github.com/google/uuid"
+)
+
+// TransferState is the lifecycle state of an asynchronously submitted transfer.
+type TransferState string
+
+const (
+	TransferPending   TransferState = "pending"   // accepted, not finished yet
+	TransferCompleted TransferState = "completed" // finished; Result holds the outcome
+	TransferFailed    TransferState = "failed"    // finished with an error
+)
+
+// String returns the string representation of the TransferState.
+func (s TransferState) String() string {
+	return string(s)
+}
+
+// TransferStatus is the tracked state of an asynchronously submitted transfer.
+type TransferStatus struct {
+	TransactionID uuid.UUID
+	ClientID      string // who submitted it; only they may poll it
+	State         TransferState
+	Result        inbound.TransferResult // set when State is TransferCompleted
+	Error         string                 // set when State is TransferFailed
+	AcceptedAt    time.Time
+	CompletedAt   time.Time
+}
+
+// TransferStatuses tracks asynchronously submitted transfers until clients poll them.
+type TransferStatuses interface {
+	Put(st TransferStatus)
+	Get(id uuid.UUID) (TransferStatus, bool)
+}
+
+type transactionIDKey struct{}
+
+// WithTransactionID returns a copy of ctx carrying a transaction ID assigned up front
+// (e.g. when a transfer is accepted asynchronously); executors should use it.
+func WithTransactionID(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, transactionIDKey{}, id)
+}
+
+// TransactionIDFrom retrieves the pre-assigned transaction ID from the context, if any.
+func TransactionIDFrom(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(transactionIDKey{}).(uuid.UUID)
+	return id, ok && id != uuid.Nil
+}
@@ -0,0 +1,56 @@
+package outbound
+
+import "time"
+
+// WebhookSubscription is a client's registration for transfer lifecycle events.
+type WebhookSubscription struct {
+	ID                  string      `json:"id"`
+	ClientID            string      `json:"client_id"`
+	URL                 string      `json:"url"`
+	Secret              string      `json:"secret"`
+	EventTypes          []EventType `json:"event_types"` // empty => all events
+	Disabled            bool        `json:"disabled"`
+	DisabledReason      string      `json:"disabled_reason,omitempty"`
+	ConsecutiveFailures int         `json:"consecutive_failures"`
+	CreatedAt           time.Time   `json:"created_at"`
+}
+
+// Wants reports whether the subscription receives events of type t.
+func (s WebhookSubscription) Wants(t EventType) bool {
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, et := range s.EventTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookAttempt is one entry of the webhook delivery log.
+type WebhookAttempt struct {
+	DeliveryID     string    `json:"delivery_id"`
+	ClientID       string    `json:"client_id"`
+	SubscriptionID string    `json:"subscription_id"`
+	EventID        string    `json:"event_id"`
+	EventType      EventType `json:"event_type"`
+	Attempt        int       `json:"attempt"`
+	StatusCode     int       `json:"status_code,omitempty"` // 0 when no response was received
+	Error          string    `json:"error,omitempty"`
+	Outcome        string    `json:"outcome"` // delivered, retrying or failed
+	LatencyMs      int64     `json:"latency_ms"`
+	At             time.Time `json:"at"`
+}
+
+// Webhooks manages webhook subscriptions and exposes their delivery log.
+// All operations are scoped to the owning client.
+type Webhooks interface {
+	Subscribe(clientID, url string, types []EventType) (WebhookSubscription, error)
+	Unsubscribe(clientID, id string) (bool, error)
+	// Enable re-enables a subscription that was disabled after repeated failures.
+	Enable(clientID, id string) (bool, error)
+	Subscriptions(clientID string) []WebhookSubscription
+	// Deliveries returns the most recent log entries, newest first; subscriptionID "" => all.
+	Deliveries(clientID, subscriptionID string, limit int) []WebhookAttempt
+}
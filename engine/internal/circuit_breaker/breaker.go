@@ -0,0 +1,331 @@
+package circuit_breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/contracts"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+)
+
+// Compile-time check that *Breaker implements outbound.CircuitBreaker.
+var _ outbound.CircuitBreaker = (*Breaker)(nil)
+
+// State is the circuit state.
+type State string
+
+const (
+	Closed   State = "closed"
+	Open     State = "open"
+	HalfOpen State = "half_open"
+)
+
+// Outcome classifies a reported call.
+type Outcome int
+
+const (
+	Success Outcome = iota
+	Failure
+	Ignored // says nothing about the dependency's health
+)
+
+// Config tunes a Breaker.
+type Config struct {
+	Name string // used in logs, errors and metrics. "" => "default"
+	// FailureRate opens the circuit once failures/requests in the window reach it.
+	// Outside (0, 1] => 0.5.
+	FailureRate float64
+	// MinRequests is the window volume needed before FailureRate applies. <= 0 => 20.
+	MinRequests int
+	Window      time.Duration // sliding window length. <= 0 => 10s
+	Buckets     int           // window granularity; outcomes expire bucket by bucket. <= 0 => 10
+	// OpenFor is how long an open circuit fails fast before probing. <= 0 => 5s.
+	OpenFor time.Duration
+	// Probes is how many calls half-open admits; all must succeed to close. <= 0 => 3.
+	Probes int
+	// Classify maps a reported error to an outcome. nil => DefaultClassify.
+	Classify func(err error) Outcome
+}
+
+// DefaultClassify counts internal errors and apperr.Timeout as failures. A bare
+// context error is the caller's: it cancelled, or its own deadline passed before
+// the dependency had its full budget, so it is ignored like apperr.Overloaded
+// (shedding, another open circuit). A call whose own budget expired must report
+// apperr.Timeout to count. Any other error means the dependency answered and
+// counts as a success.
+func DefaultClassify(err error) Outcome {
+	switch {
+	case err == nil:
+		return Success
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return Ignored
+	}
+	switch apperr.As(err).Code {
+	case apperr.CodeInternal, apperr.CodeTimeout:
+		return Failure
+	case apperr.CodeOverloaded:
+		return Ignored
+	default:
+		return Success
+	}
+}
+
+type bucket struct{ requests, failures int }
+
+// Breaker is a failure-rate circuit breaker. It is safe for concurrent use.
+type Breaker struct {
+	cfg    Config
+	logger platform.Logger
+
+	mu       sync.Mutex
+	state    State
+	gen      uint64 // bumped on every transition; stale reports are discarded
+	since    time.Time
+	buckets  []bucket // ring; cur is the newest
+	cur      int
+	curStart time.Time
+	probes   int // half-open: admitted probes still counting
+	probeOK  int // half-open: probes that succeeded
+	opened   int64
+	rejected int64
+}
+
+// New creates a closed Breaker.
+func New(cfg Config, logger platform.Logger) *Breaker {
+	if cfg.Name == "" {
+		cfg.Name = "default"
+	}
+	if cfg.FailureRate <= 0 || cfg.FailureRate > 1 {
+		cfg.FailureRate = 0.5
+	}
+	if cfg.MinRequests <= 0 {
+		cfg.MinRequests = 20
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = 10 * time.Second
+	}
+	if cfg.Buckets <= 0 {
+		cfg.Buckets = 10
+	}
+	if cfg.OpenFor <= 0 {
+		cfg.OpenFor = 5 * time.Second
+	}
+	if cfg.Probes <= 0 {
+		cfg.Probes = 3
+	}
+	if cfg.Classify == nil {
+		cfg.Classify = DefaultClassify
+	}
+	now := time.Now()
+	return &Breaker{
+		cfg:      cfg,
+		logger:   logger,
+		state:    Closed,
+		since:    now,
+		buckets:  make([]bucket, cfg.Buckets),
+		curStart: now,
+	}
+}
+
+// Allow implements outbound.CircuitBreaker.
+func (b *Breaker) Allow() (func(err error), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	switch b.state {
+	case Open:
+		if wait := b.since.Add(b.cfg.OpenFor).Sub(now); wait > 0 {
+			b.rejected++
+			return nil, b.openErr(wait)
+		}
+		b.transition(HalfOpen, now)
+		fallthrough
+	case HalfOpen:
+		if b.probes >= b.cfg.Probes {
+			b.rejected++
+			return nil, b.openErr(b.cfg.OpenFor)
+		}
+		b.probes++
+	}
+
+	gen := b.gen
+	var reported atomic.Bool
+	return func(err error) {
+		if reported.CompareAndSwap(false, true) {
+			b.report(gen, err)
+		}
+	}, nil
+}
+
+// Call runs fn through b: it fails fast while the circuit is open and reports fn's
+// error otherwise. Use it to guard outbound adapters other than the dispatcher.
+// With DefaultClassify, fn must report the expiry of its own timeout as
+// apperr.Timeout: a bare context.DeadlineExceeded is taken for the caller's.
+func Call[T any](b outbound.CircuitBreaker, fn func() (T, error)) (T, error) {
+	done, err := b.Allow()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	res, err := fn()
+	done(err)
+	return res, err
+}
+
+func (b *Breaker) report(gen uint64, err error) {
+	o := b.cfg.Classify(err)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if gen != b.gen {
+		return // admitted before the last transition
+	}
+
+	now := time.Now()
+	switch b.state {
+	case Closed:
+		if o == Ignored {
+			return
+		}
+		b.advance(now)
+		bk := &b.buckets[b.cur]
+		bk.requests++
+		if o == Failure {
+			bk.failures++
+			if req, fail := b.totals(); req >= b.cfg.MinRequests && float64(fail)/float64(req) >= b.cfg.FailureRate {
+				b.transition(Open, now)
+			}
+		}
+	case HalfOpen:
+		switch o {
+		case Ignored:
+			b.probes-- // free the slot for another probe
+		case Failure:
+			b.transition(Open, now)
+		case Success:
+			b.probeOK++
+			if b.probeOK >= b.cfg.Probes {
+				b.transition(Closed, now)
+			}
+		}
+	}
+}
+
+// transition moves to state to and logs it. Caller must hold b.mu.
+func (b *Breaker) transition(to State, now time.Time) {
+	from := b.state
+	req, fail := b.totals()
+	b.state, b.since = to, now
+	b.gen++
+	b.probes, b.probeOK = 0, 0
+
+	fields := []platform.Field{
+		{Key: "breaker", Value: b.cfg.Name},
+		{Key: "from", Value: string(from)},
+		{Key: "to", Value: string(to)},
+	}
+	switch to {
+	case Open:
+		b.opened++
+		if from == Closed {
+			fields = append(fields,
+				platform.Field{Key: "requests", Value: req},
+				platform.Field{Key: "failures", Value: fail},
+			)
+		}
+		fields = append(fields, platform.Field{Key: "open_for", Value: b.cfg.OpenFor.String()})
+		b.log(b.logger.Warn, "circuit opened", fields)
+	case HalfOpen:
+		b.log(b.logger.Info, "circuit half-open, probing", fields)
+	case Closed:
+		for i := range b.buckets {
+			b.buckets[i] = bucket{}
+		}
+		b.curStart = now
+		b.log(b.logger.Info, "circuit closed", fields)
+	}
+}
+
+func (b *Breaker) log(fn func(string, ...platform.Field), msg string, fields []platform.Field) {
+	if b.logger != nil {
+		fn(msg, fields...)
+	}
+}
+
+// advance rotates the window up to now. Caller must hold b.mu.
+func (b *Breaker) advance(now time.Time) {
+	width := b.cfg.Window / time.Duration(len(b.buckets))
+	if now.Sub(b.curStart) >= b.cfg.Window {
+		for i := range b.buckets {
+			b.buckets[i] = bucket{}
+		}
+		b.curStart = now
+		return
+	}
+	for now.Sub(b.curStart) >= width {
+		b.cur = (b.cur + 1) % len(b.buckets)
+		b.buckets[b.cur] = bucket{}
+		b.curStart = b.curStart.Add(width)
+	}
+}
+
+// totals sums the window. Caller must hold b.mu.
+func (b *Breaker) totals() (requests, failures int) {
+	for _, bk := range b.buckets {
+		requests += bk.requests
+		failures += bk.failures
+	}
+	return requests, failures
+}
+
+func (b *Breaker) openErr(retryAfter time.Duration) error {
+	return apperr.Overloaded(fmt.Sprintf("circuit %s open", b.cfg.Name)).WithRetryAfter(retryAfter)
+}
+
+// Stats is a point-in-time view of the breaker for observability.
+type Stats struct {
+	Name     string
+	State    State
+	Since    time.Time // last transition
+	Requests int       // counted outcomes in the window (closed state)
+	Failures int
+	Opened   int64 // transitions to open
+	Rejected int64 // calls failed fast
+}
+
+// Stats returns a snapshot for observability.
+func (b *Breaker) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance(time.Now())
+	req, fail := b.totals()
+	return Stats{
+		Name:     b.cfg.Name,
+		State:    b.state,
+		Since:    b.since,
+		Requests: req,
+		Failures: fail,
+		Opened:   b.opened,
+		Rejected: b.rejected,
+	}
+}
+
+// Snapshot returns the breaker's stats as a wire contract.
+func (b *Breaker) Snapshot() contracts.BreakerSnapshot {
+	st := b.Stats()
+	return contracts.BreakerSnapshot{
+		State:    string(st.State),
+		Since:    st.Since.UTC(),
+		Requests: st.Requests,
+		Failures: st.Failures,
+		Opened:   st.Opened,
+		Rejected: st.Rejected,
+	}
+}
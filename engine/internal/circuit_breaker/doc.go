@@ -0,0 +1,19 @@
+// Package circuit_breaker provides a failure-rate circuit breaker that implements
+// outbound.CircuitBreaker. It guards the dispatcher through the circuit_breaker
+// policy stage, and any other outbound adapter through Call.
+//
+// Design goals:
+//   - Closed, open and half-open states: closed admits everything, open fails
+//     fast with a retryable apperr.Overloaded (with a Retry-After hint until the
+//     next probe), half-open admits a fixed number of probes and closes only when
+//     all of them succeed.
+//   - Failure rate over a sliding time window split into buckets, so old
+//     outcomes expire gradually; below a minimum volume the circuit never opens.
+//   - Only dependency failures count (internal errors, timeouts of the call's own
+//     budget). Client errors, caller cancellations, caller deadlines and load
+//     shedding are not the dependency's fault.
+//   - Calls that straddle a transition are discarded rather than counted against
+//     the new state.
+//   - Observable: transitions are logged and Stats / Snapshot export the state,
+//     window totals and open/reject counters.
+package circuit_breaker
@@ -0,0 +1,12 @@
+// Package commit_log provides a durable outbound.CommitLog: the client-scoped
+// idempotency keys of committed transfers, appended as they commit.
+//
+// Design goals:
+//   - Durable: each commit is appended and fsynced before Committed returns, so
+//     a transfer the log does not know did not commit while it was covering.
+//   - Honest about its horizon: the log knows since when it is complete (its
+//     creation, or the retention cut-off after pruning), and Lookup says so
+//     rather than answering "not committed" for a time it never saw.
+//   - Bounded: records older than the retention are pruned when the log is
+//     opened and periodically after, by rewriting it atomically.
+package commit_log
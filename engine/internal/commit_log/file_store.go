@@ -0,0 +1,204 @@
+package commit_log
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform/file_kit"
+)
+
+// Compile-time check that *FileStore implements outbound.CommitLog.
+var _ outbound.CommitLog = (*FileStore)(nil)
+
+// Config controls the commit log.
+type Config struct {
+	Path string
+	// Retention is how long commits are kept, and so how old a dead letter may be
+	// and still be replayed. <= 0 => 7 days.
+	Retention time.Duration
+	// PruneInterval is how often commits past Retention are pruned. <= 0 => 1h.
+	PruneInterval time.Duration
+}
+
+// record is one line of the log. The first line has no key: its At is the time
+// from which the log is complete.
+type record struct {
+	Key string    `json:"key,omitempty"`
+	At  time.Time `json:"at"`
+}
+
+// FileStore is an outbound.CommitLog persisted as an append-only JSON lines file.
+// It is safe for concurrent use.
+type FileStore struct {
+	cfg Config
+
+	mu      sync.Mutex
+	f       *os.File // append handle
+	from    time.Time
+	commits map[string]time.Time // by scoped key
+	torn    bool                 // an append failed; rewrite before the next one
+}
+
+// New opens (or creates) the log at cfg.Path, prunes it and starts the pruning
+// janitor, which stops when ctx is cancelled.
+func New(ctx context.Context, cfg Config) (*FileStore, error) {
+	if cfg.Retention <= 0 {
+		cfg.Retention = 7 * 24 * time.Hour
+	}
+	if cfg.PruneInterval <= 0 {
+		cfg.PruneInterval = time.Hour
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("commit log dir: %w", err)
+	}
+	s := &FileStore{cfg: cfg, commits: make(map[string]time.Time)}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	err := s.compact(time.Now().UTC())
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		t := time.NewTicker(cfg.PruneInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				s.mu.Lock()
+				_ = s.compact(time.Now().UTC()) // retried next tick; appends still work
+				s.mu.Unlock()
+			case <-ctx.Done():
+				s.mu.Lock()
+				s.f.Close()
+				s.mu.Unlock()
+				return
+			}
+		}
+	}()
+	return s, nil
+}
+
+// Committed implements outbound.CommitLog. A transfer without a key is not
+// recorded: it cannot be looked up either.
+func (s *FileStore) Committed(clientID, key string) error {
+	if key == "" {
+		return nil
+	}
+	r := record{Key: outbound.ScopedIdempotencyKey(clientID, key), At: time.Now().UTC()}
+	line, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("encode commit: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.torn {
+		if err := s.compact(r.At); err != nil {
+			return err
+		}
+	}
+	if _, err := s.f.Write(append(line, '\n')); err != nil {
+		s.torn = true
+		return fmt.Errorf("append commit: %w", err)
+	}
+	if err := s.f.Sync(); err != nil {
+		s.torn = true
+		return fmt.Errorf("sync commit log: %w", err)
+	}
+	s.commits[r.Key] = r.At
+	return nil
+}
+
+// Lookup implements outbound.CommitLog.
+func (s *FileStore) Lookup(clientID, key string, since time.Time) (committed, covered bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.commits[outbound.ScopedIdempotencyKey(clientID, key)]; ok {
+		return true, true
+	}
+	return false, !since.Before(s.from)
+}
+
+// load reads the log. A missing file starts a log complete from now; a torn last
+// line (a crash mid-append) is skipped, its commit was never acknowledged.
+func (s *FileStore) load() error {
+	s.from = time.Now().UTC()
+	f, err := os.Open(s.cfg.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read commit log: %w", err)
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for first := true; sc.Scan(); first = false {
+		var r record
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			continue
+		}
+		if first && r.Key == "" {
+			s.from = r.At
+			continue
+		}
+		s.commits[r.Key] = r.At
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("read commit log: %w", err)
+	}
+	return nil
+}
+
+// compact drops commits past the retention, moving the log's horizon up to the
+// cut-off, and rewrites the file atomically. Caller must hold s.mu.
+func (s *FileStore) compact(now time.Time) error {
+	cutoff := now.Add(-s.cfg.Retention)
+	for key, at := range s.commits {
+		if at.Before(cutoff) {
+			delete(s.commits, key)
+		}
+	}
+	if s.from.Before(cutoff) {
+		s.from = cutoff
+	}
+
+	enc := []record{{At: s.from}}
+	for key, at := range s.commits {
+		enc = append(enc, record{Key: key, At: at})
+	}
+	var b []byte
+	for _, r := range enc {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return fmt.Errorf("encode commit log: %w", err)
+		}
+		b = append(append(b, line...), '\n')
+	}
+	if err := file_kit.WriteAtomic(s.cfg.Path, b, 0o600); err != nil {
+		return fmt.Errorf("persist commit log: %w", err)
+	}
+
+	f, err := os.OpenFile(s.cfg.Path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open commit log: %w", err)
+	}
+	if s.f != nil {
+		s.f.Close()
+	}
+	s.f, s.torn = f, false
+	return nil
+}
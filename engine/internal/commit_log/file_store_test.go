@@ -0,0 +1,91 @@
+package commit_log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func open(t *testing.T, path string) *FileStore {
+	t.Helper()
+	s, err := New(t.Context(), Config{Path: path, Retention: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestFileStoreKeepsCommitsAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "commits.log")
+	before := time.Now().Add(-time.Minute)
+	s := open(t, path)
+	if err := s.Committed("acme", "k1"); err != nil {
+		t.Fatal(err)
+	}
+
+	s = open(t, path)
+	if committed, _ := s.Lookup("acme", "k1", time.Now()); !committed {
+		t.Fatal("k1 not committed after reopening")
+	}
+	if committed, _ := s.Lookup("globex", "k1", time.Now()); committed {
+		t.Fatal("another client's key reported committed")
+	}
+	if _, covered := s.Lookup("acme", "k2", time.Now()); !covered {
+		t.Fatal("log started before now, but a missing key is not covered")
+	}
+	if _, covered := s.Lookup("acme", "k2", before); covered {
+		t.Fatal("log started after since, but a missing key is covered")
+	}
+}
+
+func TestFileStorePrunesPastRetentionAndMovesItsHorizon(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "commits.log")
+	s := open(t, path)
+	if err := s.Committed("acme", "k1"); err != nil {
+		t.Fatal(err)
+	}
+	since := time.Now()
+
+	later := time.Now().Add(2 * time.Hour)
+	s.mu.Lock()
+	err := s.compact(later)
+	s.mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, s := range map[string]*FileStore{"live": s, "reopened": open(t, path)} {
+		committed, covered := s.Lookup("acme", "k1", since)
+		if committed || covered {
+			t.Fatalf("%s: Lookup = %v, %v; want a pruned commit to be neither committed nor covered", name, committed, covered)
+		}
+	}
+}
+
+func TestFileStoreSkipsATornLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "commits.log")
+	s := open(t, path)
+	if err := s.Committed("acme", "k1"); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"key":"4:acme/k2","at":"20`) // crash mid-append
+	f.Close()
+
+	s = open(t, path)
+	if committed, _ := s.Lookup("acme", "k1", time.Now()); !committed {
+		t.Fatal("k1 lost to a torn line after it")
+	}
+	if committed, _ := s.Lookup("acme", "k2", time.Now()); committed {
+		t.Fatal("torn k2 reported committed")
+	}
+	if err := s.Committed("acme", "k3"); err != nil {
+		t.Fatal(err)
+	}
+	if committed, _ := open(t, path).Lookup("acme", "k3", time.Now()); !committed {
+		t.Fatal("k3, appended after the torn line was dropped, not committed")
+	}
+}
@@ -0,0 +1,11 @@
+// Package dead_letter provides a durable store for transfer jobs that failed for
+// internal reasons, so an operator can inspect, replay or discard them later.
+//
+// Design goals:
+//   - Durable: every mutation is persisted before it is acknowledged, and the
+//     file is replaced atomically (write temp file, fsync, rename).
+//   - Keyed by idempotency key: a transfer that keeps failing is one entry with
+//     a growing attempt count, never a pile of duplicates.
+//   - Small: failures are rare, so a single snapshot file is rewritten on each
+//     change instead of maintaining an append log.
+package dead_letter
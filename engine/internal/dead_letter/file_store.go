@@ -0,0 +1,159 @@
+package dead_letter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+	"fintech-capstone/m/v2/internal/platform/file_kit"
+
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// Compile-time check that *FileStore implements outbound.DeadLetters.
+var _ outbound.DeadLetters = (*FileStore)(nil)
+
+// FileStore is an outbound.DeadLetters persisted as a JSON snapshot file.
+// It is safe for concurrent use.
+type FileStore struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]outbound.DeadLetter
+}
+
+// NewFileStore opens (or creates) the dead-letter file at path and loads its entries.
+func NewFileStore(path string) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("dead letter dir: %w", err)
+	}
+	s := &FileStore{path: path, entries: make(map[string]outbound.DeadLetter)}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read dead letters: %w", err)
+	}
+	var list []outbound.DeadLetter
+	if err := json.Unmarshal(b, &list); err != nil {
+		return nil, fmt.Errorf("decode dead letters: %w", err)
+	}
+	for _, d := range list {
+		s.entries[d.ID] = d
+	}
+	return s, nil
+}
+
+// Capture implements outbound.DeadLetters.
+func (s *FileStore) Capture(meta hexa_inbound.RequestMeta, cmd inbound.TransferCommand, cause error) error {
+	now := time.Now().UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := outbound.ScopedIdempotencyKey(meta.ClientID, cmd.IdempotencyKey())
+	d, ok := s.entries[id]
+	if !ok {
+		d = outbound.DeadLetter{
+			ID:             id,
+			IdempotencyKey: cmd.IdempotencyKey(),
+			FromAccount:    cmd.FromAccount(),
+			ToAccount:      cmd.ToAccount(),
+			AmountCents:    cmd.AmountCents(),
+			Meta:           meta,
+			FirstFailedAt:  now,
+		}
+	}
+	d.Error = cause.Error()
+	if e := apperr.As(cause); e.Err != nil && e.Err.Error() != d.Error {
+		d.Error += ": " + e.Err.Error() // keep the root cause (e.g. the panic value)
+	}
+	d.Attempts++
+	d.LastFailedAt = now
+
+	prev, existed := s.entries[d.ID]
+	s.entries[d.ID] = d
+	if err := s.persist(); err != nil {
+		// Keep memory and disk in agreement.
+		if existed {
+			s.entries[d.ID] = prev
+		} else {
+			delete(s.entries, d.ID)
+		}
+		return err
+	}
+	return nil
+}
+
+// List implements outbound.DeadLetters. Entries are ordered oldest failure first.
+func (s *FileStore) List() []outbound.DeadLetter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sorted()
+}
+
+// Get implements outbound.DeadLetters.
+func (s *FileStore) Get(id string) (outbound.DeadLetter, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.entries[id]
+	return d, ok
+}
+
+// Discard implements outbound.DeadLetters. It reports whether the entry existed.
+func (s *FileStore) Discard(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.entries[id]
+	if !ok {
+		return false, nil
+	}
+	delete(s.entries, id)
+	if err := s.persist(); err != nil {
+		s.entries[id] = d
+		return false, err
+	}
+	return true, nil
+}
+
+// sorted returns the entries ordered by first failure. Caller must hold s.mu.
+func (s *FileStore) sorted() []outbound.DeadLetter {
+	list := make([]outbound.DeadLetter, 0, len(s.entries))
+	for _, d := range s.entries {
+		list = append(list, d)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].FirstFailedAt.Equal(list[j].FirstFailedAt) {
+			return list[i].ID < list[j].ID
+		}
+		return list[i].FirstFailedAt.Before(list[j].FirstFailedAt)
+	})
+	return list
+}
+
+// persist replaces the file with the current entries. Caller must hold s.mu.
+// A failed write leaves the previous snapshot in place, so Capture and Discard can
+// roll memory back to match it.
+func (s *FileStore) persist() error {
+	b, err := json.MarshalIndent(s.sorted(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode dead letters: %w", err)
+	}
+
+	if err := file_kit.WriteAtomic(s.path, b, 0o600); err != nil {
+		return fmt.Errorf("persist dead letters: %w", err)
+	}
+	return nil
+}
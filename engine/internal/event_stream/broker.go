@@ -0,0 +1,193 @@
+package event_stream
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+)
+
+// Compile-time checks that *Broker implements the event ports.
+var (
+	_ outbound.EventPublisher = (*Broker)(nil)
+	_ outbound.EventStream    = (*Broker)(nil)
+)
+
+// Config bounds the broker's memory.
+type Config struct {
+	// BufferSize is the number of undelivered events held per watcher. <= 0 => 64.
+	BufferSize int
+	// ReplaySize is the number of recent events kept for resume. <= 0 => 1024.
+	ReplaySize int
+	// MaxWatchers caps concurrent watchers. <= 0 => 1024.
+	MaxWatchers int
+}
+
+// Broker is an in-memory outbound.EventStream fed as an outbound.EventPublisher.
+// It is safe for concurrent use.
+type Broker struct {
+	cfg   Config
+	epoch string
+
+	mu       sync.Mutex
+	seq      uint64
+	ring     []outbound.StreamEvent // circular, oldest at ring[head] once full
+	head     int
+	watchers map[*subscription]struct{}
+
+	dropped atomic.Int64
+}
+
+// New creates a Broker.
+func New(cfg Config) *Broker {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 64
+	}
+	if cfg.ReplaySize <= 0 {
+		cfg.ReplaySize = 1024
+	}
+	if cfg.MaxWatchers <= 0 {
+		cfg.MaxWatchers = 1024
+	}
+	return &Broker{
+		cfg:      cfg,
+		epoch:    strconv.FormatInt(time.Now().UnixNano(), 36),
+		ring:     make([]outbound.StreamEvent, 0, cfg.ReplaySize),
+		watchers: make(map[*subscription]struct{}),
+	}
+}
+
+// Publish implements outbound.EventPublisher. It never blocks: watchers that are
+// behind lose the event and are told so through their lag signal.
+func (b *Broker) Publish(ev outbound.TransferEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	se := outbound.StreamEvent{ID: b.id(b.seq), Event: ev}
+	if len(b.ring) < b.cfg.ReplaySize {
+		b.ring = append(b.ring, se)
+	} else {
+		b.ring[b.head] = se
+		b.head = (b.head + 1) % len(b.ring)
+	}
+
+	for s := range b.watchers {
+		if !s.filter.Matches(ev) {
+			continue
+		}
+		select {
+		case s.events <- se:
+		default:
+			s.dropped.Add(1)
+			b.dropped.Add(1)
+			select {
+			case s.lagged <- struct{}{}:
+			default:
+			}
+		}
+	}
+	return nil
+}
+
+// Watch implements outbound.EventStream. The replay is taken under the same lock
+// that registers the watcher, so no event falls between replay and live delivery.
+func (b *Broker) Watch(f outbound.StreamFilter, lastEventID string) (outbound.EventSubscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.watchers) >= b.cfg.MaxWatchers {
+		return nil, apperr.Overloaded("too many event stream watchers")
+	}
+	s := &subscription{
+		broker: b,
+		filter: f,
+		events: make(chan outbound.StreamEvent, b.cfg.BufferSize),
+		lagged: make(chan struct{}, 1),
+	}
+	if lastEventID != "" {
+		s.replay, s.gap = b.replayAfter(f, lastEventID)
+	}
+	b.watchers[s] = struct{}{}
+	return s, nil
+}
+
+// Stats is a point-in-time view of the broker for observability.
+type Stats struct {
+	Watchers int
+	LastID   string
+	Dropped  int64 // events dropped across all watchers because they fell behind
+}
+
+// Stats returns a snapshot for observability.
+func (b *Broker) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return Stats{Watchers: len(b.watchers), LastID: b.id(b.seq), Dropped: b.dropped.Load()}
+}
+
+// replayAfter returns the buffered events after lastEventID that match f, and
+// whether events since lastEventID may be missing. Caller must hold b.mu.
+func (b *Broker) replayAfter(f outbound.StreamFilter, lastEventID string) ([]outbound.StreamEvent, bool) {
+	epoch, seqStr, ok := strings.Cut(lastEventID, "-")
+	last, err := strconv.ParseUint(seqStr, 10, 64)
+	if !ok || err != nil || epoch != b.epoch || last > b.seq {
+		// Unknown or from a previous process: everything buffered is new to the client.
+		return b.matching(f, 0), true
+	}
+	oldest := b.seq - uint64(len(b.ring)) + 1
+	return b.matching(f, last), last+1 < oldest
+}
+
+// matching returns buffered events with seq > after that pass f, oldest first.
+// Caller must hold b.mu.
+func (b *Broker) matching(f outbound.StreamFilter, after uint64) []outbound.StreamEvent {
+	var out []outbound.StreamEvent
+	oldest := b.seq - uint64(len(b.ring)) + 1
+	for i := range b.ring {
+		seq := oldest + uint64(i)
+		if seq <= after {
+			continue
+		}
+		se := b.ring[(b.head+i)%len(b.ring)]
+		if f.Matches(se.Event) {
+			out = append(out, se)
+		}
+	}
+	return out
+}
+
+func (b *Broker) id(seq uint64) string {
+	return b.epoch + "-" + strconv.FormatUint(seq, 10)
+}
+
+func (b *Broker) remove(s *subscription) {
+	b.mu.Lock()
+	delete(b.watchers, s)
+	b.mu.Unlock()
+}
+
+// subscription implements outbound.EventSubscription.
+type subscription struct {
+	broker *Broker
+	filter outbound.StreamFilter
+	events chan outbound.StreamEvent
+	lagged chan struct{}
+	replay []outbound.StreamEvent
+	gap    bool
+
+	dropped   atomic.Uint64
+	closeOnce sync.Once
+}
+
+func (s *subscription) Replay() ([]outbound.StreamEvent, bool) { return s.replay, s.gap }
+func (s *subscription) Events() <-chan outbound.StreamEvent    { return s.events }
+func (s *subscription) Lagged() <-chan struct{}                { return s.lagged }
+func (s *subscription) TakeDropped() uint64                    { return s.dropped.Swap(0) }
+
+// Close unregisters the watcher. Events already buffered are discarded.
+func (s *subscription) Close() { s.closeOnce.Do(func() { s.broker.remove(s) }) }
@@ -0,0 +1,116 @@
+package event_stream
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+)
+
+// publish publishes n events from account, numbered from 1 in their IDs.
+func publish(t *testing.T, b *Broker, n int, account string) {
+	t.Helper()
+	for i := range n {
+		ev := outbound.TransferEvent{ID: strconv.Itoa(i + 1), ClientID: "acme", FromAccount: account, ToAccount: "x"}
+		if err := b.Publish(ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// seqs returns the broker sequence numbers of events.
+func seqs(t *testing.T, b *Broker, events []outbound.StreamEvent) []uint64 {
+	t.Helper()
+	var out []uint64
+	for _, se := range events {
+		seq, err := strconv.ParseUint(se.ID[len(b.epoch)+1:], 10, 64)
+		if err != nil {
+			t.Fatalf("event id %q: %v", se.ID, err)
+		}
+		out = append(out, seq)
+	}
+	return out
+}
+
+func TestBrokerResumesFromTheRingAfterItWraps(t *testing.T) {
+	b := New(Config{ReplaySize: 4})
+	publish(t, b, 10, "a") // the ring keeps 7..10
+
+	for name, tc := range map[string]struct {
+		last   string
+		replay []uint64
+		gap    bool
+	}{
+		"evicted resume point": {last: b.id(5), replay: []uint64{7, 8, 9, 10}, gap: true},
+		"just before oldest":   {last: b.id(6), replay: []uint64{7, 8, 9, 10}},
+		"inside the ring":      {last: b.id(9), replay: []uint64{10}},
+		"up to date":           {last: b.id(10)},
+		"previous process":     {last: "oldepoch-9", replay: []uint64{7, 8, 9, 10}, gap: true},
+		"ahead of the broker":  {last: b.id(11), replay: []uint64{7, 8, 9, 10}, gap: true},
+		"malformed":            {last: "garbage", replay: []uint64{7, 8, 9, 10}, gap: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			sub, err := b.Watch(outbound.StreamFilter{ClientID: "acme"}, tc.last)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sub.Close()
+			replay, gap := sub.Replay()
+			if got := seqs(t, b, replay); !slices.Equal(got, tc.replay) || gap != tc.gap {
+				t.Fatalf("replay %v gap %v, want %v gap %v", got, gap, tc.replay, tc.gap)
+			}
+		})
+	}
+}
+
+func TestBrokerReplaysOnlyMatchingEvents(t *testing.T) {
+	b := New(Config{ReplaySize: 8})
+	publish(t, b, 2, "a")
+	publish(t, b, 2, "b")
+	publish(t, b, 2, "a")
+
+	sub, err := b.Watch(outbound.StreamFilter{Account: "a"}, b.id(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+	replay, gap := sub.Replay()
+	if got := seqs(t, b, replay); !slices.Equal(got, []uint64{2, 5, 6}) || gap {
+		t.Fatalf("replay %v gap %v, want [2 5 6] without a gap", got, gap)
+	}
+}
+
+func TestBrokerDropsForAWatcherThatLags(t *testing.T) {
+	b := New(Config{BufferSize: 2})
+	sub, err := b.Watch(outbound.StreamFilter{Account: "a"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	publish(t, b, 5, "a")
+	publish(t, b, 3, "b") // filtered out: neither buffered nor dropped
+
+	select {
+	case <-sub.Lagged():
+	default:
+		t.Fatal("lag not signalled")
+	}
+	if got := sub.TakeDropped(); got != 3 {
+		t.Fatalf("dropped = %d, want 3", got)
+	}
+	if got := sub.TakeDropped(); got != 0 {
+		t.Fatalf("dropped after take = %d, want 0", got)
+	}
+	if got := b.Stats().Dropped; got != 3 {
+		t.Fatalf("broker dropped = %d, want 3", got)
+	}
+	var got []uint64
+	for range 2 {
+		got = append(got, seqs(t, b, []outbound.StreamEvent{<-sub.Events()})...)
+	}
+	if !slices.Equal(got, []uint64{1, 2}) {
+		t.Fatalf("delivered %v, want the oldest two [1 2]", got)
+	}
+}
@@ -0,0 +1,12 @@
+// Package event_stream fans transfer lifecycle events out to live watchers
+// (Server-Sent Events and gRPC server streaming).
+//
+// Design goals:
+//   - Never block publishers: each watcher has a bounded buffer; when it is full
+//     the event is dropped for that watcher and a lag signal carries the count.
+//   - Resumable: events get ordered IDs ("<epoch>-<seq>") and the last ReplaySize
+//     events are kept, so a reconnecting client catches up from its last ID. IDs
+//     from an earlier process (another epoch) or older than the buffer are
+//     reported as a gap instead of being silently skipped.
+//   - Bounded: no goroutine per watcher or per event; the number of watchers is capped.
+package event_stream
@@ -0,0 +1,25 @@
+package event_stream
+
+import (
+	"errors"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+)
+
+// Compile-time check that Fanout implements outbound.EventPublisher.
+var _ outbound.EventPublisher = Fanout(nil)
+
+// Fanout is an outbound.EventPublisher that publishes to every publisher in turn,
+// e.g. webhooks and the live stream. A failing publisher does not stop the others.
+type Fanout []outbound.EventPublisher
+
+// Publish implements outbound.EventPublisher.
+func (f Fanout) Publish(ev outbound.TransferEvent) error {
+	var errs []error
+	for _, p := range f {
+		if err := p.Publish(ev); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
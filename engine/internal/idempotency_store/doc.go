@@ -0,0 +1,25 @@
+// Package idempotency_store provides a bounded, expiring in-memory store that
+// implements outbound.Idempotency, outbound.RecordIdempotency and
+// outbound.ClaimIdempotency.
+//
+// Design goals:
+//   - Each result is kept with the fingerprint of the request that produced it,
+//     so a key reused with a different payload can be rejected.
+//   - Per-entry TTL: results are kept for the retention of the route that stored
+//     them (e.g. 24h for payments) and for a default TTL otherwise; a route with
+//     zero retention is not stored at all.
+//   - Failures are stored only for the error codes a route opts into, and never
+//     when transient (timeout, rate limited, overloaded, internal), so a retry
+//     gets the same final answer but may still succeed after a passing fault.
+//   - In-flight claims: Claim atomically returns a key's record or marks it in
+//     progress, so concurrent duplicates wait for the first result instead of
+//     executing again. Claims expire after ClaimTTL if never released.
+//   - Bounded: an entry count and, with a Size func, an approximate byte budget
+//     cap memory. When full, the least recently used entry is evicted first.
+//   - Sharded by key hash, each shard with its own map, LRU list and bounds, to
+//     keep lock contention low.
+//   - Expired entries are misses immediately and are removed lazily on access
+//     and by a background janitor.
+//   - Cheap observability: hits, misses, evictions, expirations, entries, bytes,
+//     keys in flight, contended and expired claims.
+package idempotency_store
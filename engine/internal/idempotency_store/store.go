@@ -0,0 +1,339 @@
+package idempotency_store
+
+import (
+	"container/list"
+	"context"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/contracts"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// Compile-time check that *Store implements the idempotency ports.
+var _ outbound.ClaimIdempotency[hexa_inbound.Result] = (*Store[hexa_inbound.Result])(nil)
+
+// Config controls retention and bounds.
+type Config[T any] struct {
+	// TTL keeps results stored without a route (or for an unlisted route). <= 0 => 24h.
+	TTL time.Duration
+	// Routes overrides TTL by route (RequestMeta.Target). <= 0 => not stored.
+	Routes map[string]time.Duration
+	// Failures lists by route the error codes whose failures are stored and
+	// replayed like results. Unlisted routes store successes only, and failures
+	// that are not apperr.IsTerminal are never stored.
+	Failures map[string][]apperr.Code
+	// MaxEntries bounds the entry count across all shards. <= 0 => 100_000.
+	MaxEntries int
+	// MaxBytes bounds the approximate size (key + fingerprint + Size(result)) across
+	// all shards.
+	// Needs Size. <= 0 => no byte bound.
+	MaxBytes int64
+	Size     func(res T) int // approximate result size in bytes. nil => count only
+	// NumShards should be well below MaxEntries: each shard holds an equal share. <= 0 => 64.
+	NumShards       int
+	CleanupInterval time.Duration // <= 0 => 1m
+	// ClaimTTL bounds how long a key stays in progress if its owner never releases
+	// it (e.g. it crashed). Keep it above the longest request. <= 0 => 1m.
+	ClaimTTL time.Duration
+}
+
+type entry[T any] struct {
+	key     string
+	rec     outbound.IdempotencyRecord[T]
+	size    int64
+	expires time.Time
+}
+
+type shard[T any] struct {
+	mu     sync.Mutex
+	data   map[string]*list.Element // of *entry[T]
+	lru    list.List                // front = most recently used
+	bytes  int64
+	claims map[string]*claim // keys in progress; not counted against the bounds
+}
+
+type claim struct {
+	fingerprint string
+	done        chan struct{}
+	timer       *time.Timer // expires the claim
+	once        sync.Once
+}
+
+// Store is a sharded, TTL-expiring, LRU-bounded idempotency store. It is safe for
+// concurrent use.
+type Store[T any] struct {
+	cfg        Config[T]
+	shards     []shard[T]
+	maxEntries int   // per shard
+	maxBytes   int64 // per shard; 0 => no byte bound
+
+	hits, misses, evictions, expirations atomic.Int64
+	contended, claimsExpired             atomic.Int64
+
+	cleanupTicker   *time.Ticker
+	stopCleanupChan chan struct{}
+	stopOnce        sync.Once
+}
+
+// New creates a Store. Provide a context that is cancelled on server shutdown to
+// stop background cleanup.
+func New[T any](ctx context.Context, cfg Config[T]) *Store[T] {
+	if cfg.TTL <= 0 {
+		cfg.TTL = 24 * time.Hour
+	}
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = 100_000
+	}
+	if cfg.NumShards <= 0 {
+		cfg.NumShards = 64
+	}
+	if cfg.CleanupInterval <= 0 {
+		cfg.CleanupInterval = time.Minute
+	}
+	if cfg.ClaimTTL <= 0 {
+		cfg.ClaimTTL = time.Minute
+	}
+
+	s := &Store[T]{
+		cfg:             cfg,
+		shards:          make([]shard[T], cfg.NumShards),
+		maxEntries:      max(1, cfg.MaxEntries/cfg.NumShards),
+		stopCleanupChan: make(chan struct{}),
+	}
+	if cfg.MaxBytes > 0 && cfg.Size != nil {
+		s.maxBytes = max(1, cfg.MaxBytes/int64(cfg.NumShards))
+	}
+	for i := range s.shards {
+		s.shards[i].data = make(map[string]*list.Element)
+		s.shards[i].claims = make(map[string]*claim)
+	}
+
+	// Background janitor: drop expired results.
+	s.cleanupTicker = time.NewTicker(cfg.CleanupInterval)
+	go func() {
+		defer s.cleanupTicker.Stop()
+		for {
+			select {
+			case <-s.cleanupTicker.C:
+				s.cleanup()
+			case <-ctx.Done():
+				return
+			case <-s.stopCleanupChan:
+				return
+			}
+		}
+	}()
+	return s
+}
+
+// Stop stops background cleanup.
+func (s *Store[T]) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCleanupChan) })
+}
+
+// Get implements outbound.Idempotency.
+func (s *Store[T]) Get(key string) (T, bool) {
+	rec, ok := s.GetRecord(key)
+	return rec.Result, ok
+}
+
+// Store implements outbound.Idempotency with the default TTL and no fingerprint.
+func (s *Store[T]) Store(key string, res T) {
+	s.put(key, outbound.IdempotencyRecord[T]{Result: res}, s.cfg.TTL)
+}
+
+// GetRecord implements outbound.RecordIdempotency.
+func (s *Store[T]) GetRecord(key string) (outbound.IdempotencyRecord[T], bool) {
+	sh := s.getShard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return s.recordLocked(sh, key)
+}
+
+// recordLocked looks key up, dropping it if expired. Caller must hold sh.mu.
+func (s *Store[T]) recordLocked(sh *shard[T], key string) (outbound.IdempotencyRecord[T], bool) {
+	el, ok := sh.data[key]
+	if !ok {
+		s.misses.Add(1)
+		return outbound.IdempotencyRecord[T]{}, false
+	}
+	e := el.Value.(*entry[T])
+	if !time.Now().Before(e.expires) {
+		sh.remove(el)
+		s.expirations.Add(1)
+		s.misses.Add(1)
+		return outbound.IdempotencyRecord[T]{}, false
+	}
+	sh.lru.MoveToFront(el)
+	s.hits.Add(1)
+	return e.rec, true
+}
+
+// Claim implements outbound.ClaimIdempotency.
+func (s *Store[T]) Claim(key, fingerprint string) outbound.IdempotencyClaim[T] {
+	sh := s.getShard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	if rec, ok := s.recordLocked(sh, key); ok {
+		return outbound.IdempotencyClaim[T]{Record: &rec}
+	}
+	if c, ok := sh.claims[key]; ok {
+		s.contended.Add(1)
+		return outbound.IdempotencyClaim[T]{Fingerprint: c.fingerprint, Done: c.done}
+	}
+
+	c := &claim{fingerprint: fingerprint, done: make(chan struct{})}
+	sh.claims[key] = c
+	c.timer = time.AfterFunc(s.cfg.ClaimTTL, func() { s.endClaim(sh, key, c, true) })
+	return outbound.IdempotencyClaim[T]{Release: func() { s.endClaim(sh, key, c, false) }}
+}
+
+// endClaim ends c once, by release or expiry, and wakes its waiters.
+func (s *Store[T]) endClaim(sh *shard[T], key string, c *claim, expired bool) {
+	c.once.Do(func() {
+		sh.mu.Lock()
+		if sh.claims[key] == c {
+			delete(sh.claims, key)
+		}
+		sh.mu.Unlock()
+		c.timer.Stop()
+		close(c.done)
+		if expired {
+			s.claimsExpired.Add(1)
+		}
+	})
+}
+
+// StoreRecord implements outbound.RecordIdempotency.
+func (s *Store[T]) StoreRecord(route, key string, rec outbound.IdempotencyRecord[T]) {
+	if rec.Err != nil && !s.keepsFailure(route, rec.Err) {
+		return
+	}
+	ttl, ok := s.cfg.Routes[route]
+	if !ok {
+		ttl = s.cfg.TTL
+	}
+	if ttl <= 0 {
+		return
+	}
+	s.put(key, rec, ttl)
+}
+
+// keepsFailure reports whether route stores err.
+func (s *Store[T]) keepsFailure(route string, err error) bool {
+	if !apperr.IsTerminal(err) {
+		return false
+	}
+	return slices.Contains(s.cfg.Failures[route], apperr.As(err).Code)
+}
+
+func (s *Store[T]) put(key string, rec outbound.IdempotencyRecord[T], ttl time.Duration) {
+	e := &entry[T]{key: key, rec: rec, size: int64(len(key) + len(rec.Fingerprint)), expires: time.Now().Add(ttl)}
+	if s.cfg.Size != nil {
+		e.size += int64(s.cfg.Size(rec.Result))
+	}
+
+	sh := s.getShard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if el, ok := sh.data[key]; ok {
+		sh.remove(el)
+	}
+	sh.data[key] = sh.lru.PushFront(e)
+	sh.bytes += e.size
+
+	// Evict least recently used entries until the shard fits, keeping the new one.
+	for sh.lru.Len() > 1 && (sh.lru.Len() > s.maxEntries || (s.maxBytes > 0 && sh.bytes > s.maxBytes)) {
+		sh.remove(sh.lru.Back())
+		s.evictions.Add(1)
+	}
+}
+
+// remove drops el from the shard. Caller must hold sh.mu.
+func (sh *shard[T]) remove(el *list.Element) {
+	e := sh.lru.Remove(el).(*entry[T])
+	delete(sh.data, e.key)
+	sh.bytes -= e.size
+}
+
+func (s *Store[T]) getShard(key string) *shard[T] {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return &s.shards[h.Sum32()%uint32(len(s.shards))]
+}
+
+// cleanup drops expired entries across shards.
+func (s *Store[T]) cleanup() (expired int) {
+	now := time.Now()
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.mu.Lock()
+		for _, el := range sh.data {
+			if !now.Before(el.Value.(*entry[T]).expires) {
+				sh.remove(el)
+				expired++
+			}
+		}
+		sh.mu.Unlock()
+	}
+	s.expirations.Add(int64(expired))
+	return
+}
+
+// Stats is a point-in-time view of the store for observability.
+type Stats struct {
+	Entries       int
+	Bytes         int64 // approximate; keys and fingerprints only without Config.Size
+	Hits          int64
+	Misses        int64
+	Evictions     int64 // dropped by the entry or byte bound before expiring
+	Expirations   int64
+	InFlight      int   // keys claimed in progress
+	Contended     int64 // claims refused because the key was in progress
+	ClaimsExpired int64 // claims that lapsed before their owner released them
+}
+
+// Stats returns a snapshot for observability.
+func (s *Store[T]) Stats() Stats {
+	st := Stats{
+		Hits:          s.hits.Load(),
+		Misses:        s.misses.Load(),
+		Evictions:     s.evictions.Load(),
+		Expirations:   s.expirations.Load(),
+		Contended:     s.contended.Load(),
+		ClaimsExpired: s.claimsExpired.Load(),
+	}
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.mu.Lock()
+		st.Entries += sh.lru.Len()
+		st.Bytes += sh.bytes
+		st.InFlight += len(sh.claims)
+		sh.mu.Unlock()
+	}
+	return st
+}
+
+// Snapshot returns the store's stats as a wire contract.
+func (s *Store[T]) Snapshot() contracts.IdempotencySnapshot {
+	st := s.Stats()
+	return contracts.IdempotencySnapshot{
+		Entries:       st.Entries,
+		Bytes:         st.Bytes,
+		Hits:          st.Hits,
+		Misses:        st.Misses,
+		Evictions:     st.Evictions,
+		Expirations:   st.Expirations,
+		InFlight:      st.InFlight,
+		Contended:     st.Contended,
+		ClaimsExpired: st.ClaimsExpired,
+	}
+}
@@ -0,0 +1,124 @@
+package idempotency_store
+
+import (
+	"testing"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+)
+
+func newStore(t *testing.T, cfg Config[string]) *Store[string] {
+	t.Helper()
+	cfg.NumShards = 1 // one LRU order for the whole store
+	s := New(t.Context(), cfg)
+	t.Cleanup(s.Stop)
+	return s
+}
+
+func TestStoreEvictsTheLeastRecentlyUsedAtMaxEntries(t *testing.T) {
+	s := newStore(t, Config[string]{MaxEntries: 2})
+	s.Store("a", "1")
+	s.Store("b", "2")
+	s.Get("a") // b is now the least recently used
+	s.Store("c", "3")
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok := s.Get(key); ok != want {
+			t.Fatalf("%s kept = %v, want %v", key, ok, want)
+		}
+	}
+	if st := s.Stats(); st.Entries != 2 || st.Evictions != 1 {
+		t.Fatalf("entries %d evictions %d, want 2 and 1", st.Entries, st.Evictions)
+	}
+}
+
+func TestStoreExpiresByRoute(t *testing.T) {
+	s := newStore(t, Config[string]{
+		TTL:    time.Hour,
+		Routes: map[string]time.Duration{"short": 10 * time.Millisecond, "none": 0},
+	})
+	rec := outbound.IdempotencyRecord[string]{Result: "ok"}
+	s.StoreRecord("short", "short", rec)
+	s.StoreRecord("none", "none", rec)
+	s.StoreRecord("unlisted", "unlisted", rec)
+	s.Store("default", "ok")
+
+	if _, ok := s.GetRecord("short"); !ok {
+		t.Fatal("short-lived record missing before its TTL")
+	}
+	time.Sleep(20 * time.Millisecond)
+	for key, want := range map[string]bool{"short": false, "none": false, "unlisted": true, "default": true} {
+		if _, ok := s.GetRecord(key); ok != want {
+			t.Fatalf("%s kept = %v, want %v", key, ok, want)
+		}
+	}
+	if got := s.Stats().Expirations; got != 1 {
+		t.Fatalf("expirations = %d, want 1", got)
+	}
+}
+
+func TestStoreKeepsOnlyFailuresTheRouteOptsInto(t *testing.T) {
+	s := newStore(t, Config[string]{Failures: map[string][]apperr.Code{
+		"transfer": {apperr.CodeInvalid, apperr.CodeTimeout},
+	}})
+	for name, tc := range map[string]struct {
+		route string
+		err   error
+		kept  bool
+	}{
+		"listed":           {"transfer", apperr.Invalid("insufficient funds"), true},
+		"unlisted code":    {"transfer", apperr.NotFound("no such account"), false},
+		"unlisted route":   {"other", apperr.Invalid("insufficient funds"), false},
+		"not terminal":     {"transfer", apperr.Timeout("timed out"), false},
+		"marked transient": {"transfer", apperr.Transient("lock wait", apperr.Invalid("x")), false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			s.StoreRecord(tc.route, name, outbound.IdempotencyRecord[string]{Err: tc.err})
+			rec, ok := s.GetRecord(name)
+			if ok != tc.kept {
+				t.Fatalf("kept = %v, want %v", ok, tc.kept)
+			}
+			if ok && rec.Err != tc.err {
+				t.Fatalf("stored %v, want %v", rec.Err, tc.err)
+			}
+		})
+	}
+}
+
+func TestStoreClaims(t *testing.T) {
+	s := newStore(t, Config[string]{ClaimTTL: 20 * time.Millisecond})
+
+	owner := s.Claim("k", "fp1")
+	if owner.Release == nil {
+		t.Fatal("first claim not granted")
+	}
+	dup := s.Claim("k", "fp2")
+	if dup.Release != nil || dup.Record != nil || dup.Fingerprint != "fp1" || dup.Done == nil {
+		t.Fatalf("duplicate claim = %+v, want to wait on the owner's fp1", dup)
+	}
+
+	select {
+	case <-dup.Done:
+	case <-time.After(time.Second):
+		t.Fatal("unreleased claim did not expire")
+	}
+	if st := s.Stats(); st.InFlight != 0 || st.ClaimsExpired != 1 || st.Contended != 1 {
+		t.Fatalf("stats %+v, want the claim expired after one contended claim", st)
+	}
+
+	next := s.Claim("k", "fp1")
+	if next.Release == nil {
+		t.Fatal("expired key not claimable again")
+	}
+	s.StoreRecord("", "k", outbound.IdempotencyRecord[string]{Result: "ok", Fingerprint: "fp1"})
+	next.Release()
+	owner.Release() // late release of the expired claim is harmless
+
+	if c := s.Claim("k", "fp1"); c.Record == nil || c.Record.Result != "ok" {
+		t.Fatalf("claim after release = %+v, want the stored record", c)
+	}
+	if got := s.Stats().ClaimsExpired; got != 1 {
+		t.Fatalf("claims expired = %d, want 1 (releases are not expiries)", got)
+	}
+}
@@ -0,0 +1,11 @@
+// Package job_journal persists dispatcher jobs that were still queued at shutdown,
+// so the next boot can resume them.
+//
+// Design goals:
+//   - Crash safe: the journal is a JSON snapshot replaced atomically (write to a
+//     temp file, fsync, rename), so a crash mid-save leaves the previous journal.
+//   - Ordered: jobs keep their submission sequence and are loaded in that order.
+//   - Exactly-once effect comes from the jobs' idempotency keys, not from the
+//     journal: resuming a job that already completed is answered by the
+//     idempotency store.
+package job_journal
@@ -0,0 +1,78 @@
+package job_journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform/file_kit"
+)
+
+// Compile-time check that *FileStore implements outbound.PendingJobs.
+var _ outbound.PendingJobs = (*FileStore)(nil)
+
+// FileStore is an outbound.PendingJobs persisted as a JSON snapshot file.
+// It is safe for concurrent use.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStore returns a FileStore writing to path, creating its directory.
+func NewFileStore(path string) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("job journal dir: %w", err)
+	}
+	return &FileStore{path: path}, nil
+}
+
+// Load implements outbound.PendingJobs. A missing file is an empty journal.
+func (s *FileStore) Load() ([]outbound.PendingJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read job journal: %w", err)
+	}
+	var jobs []outbound.PendingJob
+	if err := json.Unmarshal(b, &jobs); err != nil {
+		return nil, fmt.Errorf("decode job journal: %w", err)
+	}
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].Seq < jobs[j].Seq })
+	return jobs, nil
+}
+
+// Save implements outbound.PendingJobs. Saving no jobs removes the file. The
+// journal is replaced whole: a crash mid-save keeps the previous one, so drained
+// jobs are either all saved or still in the old journal.
+func (s *FileStore) Save(jobs []outbound.PendingJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(jobs) == 0 {
+		if err := os.Remove(s.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("clear job journal: %w", err)
+		}
+		return nil
+	}
+
+	b, err := json.MarshalIndent(jobs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode job journal: %w", err)
+	}
+
+	if err := file_kit.WriteAtomic(s.path, b, 0o600); err != nil {
+		return fmt.Errorf("persist job journal: %w", err)
+	}
+	return nil
+}
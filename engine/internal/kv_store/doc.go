@@ -0,0 +1,13 @@
+// Package kv_store provides an in-process key-value server that implements
+// outbound.AtomicKV, so features built on shared state (e.g. the distributed
+// rate limiter) run in tests and dev without an external store.
+//
+// Design goals:
+//   - Same contract as a shared store: compare-and-swap on the whole value,
+//     create-if-absent with a nil old value, and per-key TTL.
+//   - Sharded by key hash, each shard with its own lock, to keep contention low.
+//   - Expired keys are missing immediately and are removed lazily on access and
+//     by a background janitor. Expiry runs on an injectable Clock, so TTLs mean
+//     the same instant to the store and to a caller on the same clock.
+//   - Values are copied in and out, so callers never share buffers with the store.
+package kv_store
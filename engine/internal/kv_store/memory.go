@@ -0,0 +1,186 @@
+package kv_store
+
+import (
+	"bytes"
+	"context"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+)
+
+// Compile-time check that *Memory implements outbound.AtomicKV.
+var _ outbound.AtomicKV = (*Memory)(nil)
+
+// Clock tells the store the time. Expiry is measured on it, so a caller that
+// derives TTLs from its own clock (e.g. limiter.Clock) should share it.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// Config tunes a Memory store.
+type Config struct {
+	NumShards       int           // <= 0 => 64
+	CleanupInterval time.Duration // <= 0 => 1m
+	Clock           Clock         // nil => system clock
+}
+
+type item struct {
+	value   []byte
+	expires time.Time // zero => never
+}
+
+func (it item) expired(now time.Time) bool {
+	return !it.expires.IsZero() && !now.Before(it.expires)
+}
+
+type shard struct {
+	mu   sync.Mutex
+	data map[string]item
+}
+
+// Memory is an in-process outbound.AtomicKV. It is safe for concurrent use.
+type Memory struct {
+	shards []shard
+	clock  Clock
+
+	gets, swaps, conflicts atomic.Int64
+
+	stopCleanupChan chan struct{}
+	stopOnce        sync.Once
+}
+
+// NewMemory creates a Memory store. Provide a context that is cancelled on server
+// shutdown to stop background cleanup.
+func NewMemory(ctx context.Context, cfg Config) *Memory {
+	if cfg.NumShards <= 0 {
+		cfg.NumShards = 64
+	}
+	if cfg.CleanupInterval <= 0 {
+		cfg.CleanupInterval = time.Minute
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = systemClock{}
+	}
+	m := &Memory{
+		shards:          make([]shard, cfg.NumShards),
+		clock:           cfg.Clock,
+		stopCleanupChan: make(chan struct{}),
+	}
+	for i := range m.shards {
+		m.shards[i].data = make(map[string]item)
+	}
+
+	// Background janitor: drop expired keys.
+	ticker := time.NewTicker(cfg.CleanupInterval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.cleanup()
+			case <-ctx.Done():
+				return
+			case <-m.stopCleanupChan:
+				return
+			}
+		}
+	}()
+	return m
+}
+
+// Stop stops background cleanup.
+func (m *Memory) Stop() {
+	m.stopOnce.Do(func() { close(m.stopCleanupChan) })
+}
+
+// Get implements outbound.AtomicKV.
+func (m *Memory) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.gets.Add(1)
+	sh := m.getShard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	it, ok := sh.lookup(key, m.clock.Now())
+	if !ok {
+		return nil, false, nil
+	}
+	return bytes.Clone(it.value), true, nil
+}
+
+// CompareAndSwap implements outbound.AtomicKV.
+func (m *Memory) CompareAndSwap(_ context.Context, key string, old, value []byte, ttl time.Duration) (bool, error) {
+	now := m.clock.Now()
+	sh := m.getShard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	it, ok := sh.lookup(key, now)
+	if ok != (old != nil) || (ok && !bytes.Equal(it.value, old)) {
+		m.conflicts.Add(1)
+		return false, nil
+	}
+	next := item{value: bytes.Clone(value)}
+	if ttl > 0 {
+		next.expires = now.Add(ttl)
+	}
+	sh.data[key] = next
+	m.swaps.Add(1)
+	return true, nil
+}
+
+// lookup returns the key's live item, dropping it if expired. Caller must hold sh.mu.
+func (sh *shard) lookup(key string, now time.Time) (item, bool) {
+	it, ok := sh.data[key]
+	if ok && it.expired(now) {
+		delete(sh.data, key)
+		return item{}, false
+	}
+	return it, ok
+}
+
+func (m *Memory) getShard(key string) *shard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return &m.shards[h.Sum32()%uint32(len(m.shards))]
+}
+
+// cleanup drops expired keys across shards.
+func (m *Memory) cleanup() {
+	now := m.clock.Now()
+	for i := range m.shards {
+		sh := &m.shards[i]
+		sh.mu.Lock()
+		for key, it := range sh.data {
+			if it.expired(now) {
+				delete(sh.data, key)
+			}
+		}
+		sh.mu.Unlock()
+	}
+}
+
+// Stats is a point-in-time view of the store for observability.
+type Stats struct {
+	Keys      int
+	Gets      int64
+	Swaps     int64
+	Conflicts int64 // compare-and-swaps refused because the value had changed
+}
+
+// Stats returns a snapshot for observability.
+func (m *Memory) Stats() Stats {
+	st := Stats{Gets: m.gets.Load(), Swaps: m.swaps.Load(), Conflicts: m.conflicts.Load()}
+	for i := range m.shards {
+		sh := &m.shards[i]
+		sh.mu.Lock()
+		st.Keys += len(sh.data)
+		sh.mu.Unlock()
+	}
+	return st
+}
@@ -2,12 +2,19 @@ package limiter
 
 import (
 	"context"
+	"fintech-capstone/m/v2/internal/api_gateway/contracts"
 	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fmt"
 	"time"
+
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
 )
 
-// Compile-time check that *Composite implements outbound.Limiter.
-var _ outbound.Limiter = (*Composite)(nil)
+// Compile-time check that *Composite implements the limiter ports.
+var (
+	_ outbound.CostLimiter = (*Composite)(nil)
+	_ outbound.PlanLimiter = (*Composite)(nil)
+)
 
 // Composite is a limiter that enforces per-client and optional global limits.
 // It is safe for concurrent use.
@@ -15,6 +22,7 @@ type Composite struct {
 	clock  Clock
 	client *clientShardSet
 	global *tokenBucket
+	routes map[string]*routeCost // read-only after New
 
 	cfg             Config
 	cleanupTicker   *time.Ticker
@@ -22,8 +30,10 @@ type Composite struct {
 }
 
 // New creates a new Composite limiter. Provide a context that is cancelled on
-// server shutdown to stop background cleanup.
-func New(ctx context.Context, cfg Config) *Composite {
+// server shutdown to stop background cleanup. It fails if a route in cfg.Costs
+// can charge more than the global or the per-client burst: such a request could
+// never be admitted.
+func New(ctx context.Context, cfg Config) (*Composite, error) {
 	clk := cfg.Clock
 	if clk == nil {
 		clk = systemClock{}
@@ -39,11 +49,28 @@ func New(ctx context.Context, cfg Config) *Composite {
 	}
 
 	l := &Composite{
-		clock:           clk,
-		client:          newClientShardSet(cfg.NumShards, clk, cfg.PerClient),
+		clock: clk,
+		client: newClientShardSet(cfg.NumShards, clk, newPlanSet(Plans{
+			Default: DefaultPlan,
+			Plans:   map[string]PerClientConfig{DefaultPlan: cfg.PerClient},
+		})),
+		routes:          make(map[string]*routeCost, len(cfg.Costs)),
 		cfg:             cfg,
 		stopCleanupChan: make(chan struct{}),
 	}
+	for route, rc := range cfg.Costs {
+		l.routes[route] = newRouteCost(rc)
+	}
+	if cfg.PerClient.RatePerSec > 0 {
+		if err := checkCosts(l.routes, cfg.PerClient.Burst); err != nil {
+			return nil, fmt.Errorf("per-client limit: %w", err)
+		}
+	}
+	if cfg.Global != nil && cfg.Global.RatePerSec > 0 {
+		if err := checkCosts(l.routes, cfg.Global.Burst); err != nil {
+			return nil, fmt.Errorf("global limit: %w", err)
+		}
+	}
 	if cfg.Global != nil {
 		init := cfg.Global.InitialTokens
 		if init <= 0 {
@@ -59,7 +86,7 @@ func New(ctx context.Context, cfg Config) *Composite {
 		for {
 			select {
 			case <-l.cleanupTicker.C:
-				l.client.cleanup(cfg.PerClient.TTL)
+				l.client.cleanup()
 			case <-ctx.Done():
 				return
 			case <-l.stopCleanupChan:
@@ -67,7 +94,49 @@ func New(ctx context.Context, cfg Config) *Composite {
 			}
 		}
 	}()
-	return l
+	return l, nil
+}
+
+// SetPlans replaces the per-client plans (initially Config.PerClient for
+// everyone). Clients already tracked move to their new plan in place, keeping
+// their tokens up to the new burst, so a reload never resets anyone's budget.
+// Invalid plans are rejected and the plans in effect are kept.
+func (l *Composite) SetPlans(p Plans) (moved int, err error) {
+	if err := p.validate(); err != nil {
+		return 0, err
+	}
+	for name, cfg := range p.Plans {
+		if cfg.RatePerSec <= 0 {
+			continue
+		}
+		if err := checkCosts(l.routes, cfg.Burst); err != nil {
+			return 0, fmt.Errorf("plan %q: %w", name, err)
+		}
+	}
+	return l.client.setPlans(newPlanSet(p)), nil
+}
+
+// PlanFor implements outbound.PlanLimiter.
+func (l *Composite) PlanFor(clientID string) outbound.ClientPlan {
+	name, cfg, assigned := l.client.plans.Load().resolve(clientID)
+	cp := outbound.ClientPlan{
+		ClientID:   clientID,
+		Plan:       name,
+		Assigned:   assigned,
+		RatePerSec: cfg.RatePerSec,
+		Burst:      cfg.Burst,
+		TTL:        cfg.TTL,
+	}
+	if cb := l.client.lookup(clientID); cb != nil {
+		cb.b.mu.Lock()
+		cb.b.refill(l.clock.Now())
+		cp.Tracked = true
+		if !cb.b.unlimited() {
+			cp.Remaining = int(cb.b.tokens)
+		}
+		cb.b.mu.Unlock()
+	}
+	return cp
 }
 
 // Stop stops the background cleanup goroutine (optional; otherwise it exits when ctx is cancelled).
@@ -75,19 +144,39 @@ func (l *Composite) Stop() { close(l.stopCleanupChan) }
 
 // Allow implements outbound.Limiter.
 // Cost is 1 token per call; both global and per-client buckets must admit.
-func (l *Composite) Allow(clientID string) bool {
+func (l *Composite) Allow(clientID string) outbound.LimitDecision {
+	return l.allow(clientID, 1)
+}
+
+// AllowCost implements outbound.CostLimiter: it charges the cost Config.Costs
+// declares for route and cmd, or 1 token for an unlisted route.
+func (l *Composite) AllowCost(clientID, route string, cmd hexa_inbound.Command) outbound.LimitDecision {
+	rc, ok := l.routes[route]
+	if !ok {
+		return l.allow(clientID, 1)
+	}
+	cost := rc.of(cmd)
+	d := l.allow(clientID, cost)
+	rc.record(cost, d.Allowed)
+	return d
+}
+
+// allow charges cost to the client's and the global bucket atomically: both
+// must admit. A cost above a bucket's burst is refused, never capped.
+func (l *Composite) allow(clientID string, cost float64) outbound.LimitDecision {
 	now := l.clock.Now()
 
 	// Fast path: per-client only.
 	if l.global == nil {
 		cb := l.client.getOrCreate(clientID)
 		cb.b.mu.Lock()
-		allowed := cb.b.take(now, 1)
+		allowed := cb.b.take(now, cost)
 		if allowed {
 			cb.lastSeen = now
 		}
+		d := decide(allowed, cost, cb.b)
 		cb.b.mu.Unlock()
-		return allowed
+		return d
 	}
 
 	// Global + per-client with lock ordering: global.mu -> client.mu
@@ -97,20 +186,44 @@ func (l *Composite) Allow(clientID string) bool {
 	cb.b.mu.Lock()
 
 	// Lazy refill both; consume only if BOTH have tokens.
-	gHas := l.global.hasAtLeast(now, 1)
-	cHas := cb.b.hasAtLeast(now, 1)
+	gHas := l.global.hasAtLeast(now, cost)
+	cHas := cb.b.hasAtLeast(now, cost)
 	if gHas && cHas {
-		l.global.consumeNoCheck(1)
-		cb.b.consumeNoCheck(1)
+		l.global.consumeNoCheck(cost)
+		cb.b.consumeNoCheck(cost)
 		cb.lastSeen = now
-		cb.b.mu.Unlock()
-		l.global.mu.Unlock()
-		return true
 	}
+	d := decide(gHas && cHas, cost, l.global, cb.b)
 
 	cb.b.mu.Unlock()
 	l.global.mu.Unlock()
-	return false
+	return d
+}
+
+// decide builds the decision from the checked buckets, after refill and any
+// consumption. A refusal waits for the slowest bucket lacking tokens; Limit,
+// Remaining and Reset describe the bucket with the fewest tokens left. Caller
+// must hold the buckets' locks.
+func decide(allowed bool, cost float64, buckets ...*tokenBucket) outbound.LimitDecision {
+	d := outbound.LimitDecision{Allowed: allowed}
+	var tightest *tokenBucket
+	for _, b := range buckets {
+		if b.unlimited() {
+			continue
+		}
+		if w := b.wait(cost); !allowed && w > d.RetryAfter {
+			d.RetryAfter = w
+		}
+		if tightest == nil || b.tokens < tightest.tokens {
+			tightest = b
+		}
+	}
+	if tightest != nil {
+		d.Limit = int(tightest.burst)
+		d.Remaining = int(tightest.tokens)
+		d.Reset = tightest.untilFull()
+	}
+	return d
 }
 
 // Stats returns a snapshot for observability.
@@ -121,13 +234,26 @@ type Stats struct {
 	GlobalRate     float64
 	GlobalBurst    int
 	NumShards      int
+	Routes         map[string]RouteCostStats // priced routes (Config.Costs)
+	DefaultPlan    string                    // PerClientRate/Burst are this plan's
+	Plans          map[string]PerClientConfig
+	AssignedPlans  int // clients with an explicit plan
 }
 
 func (l *Composite) Stats() Stats {
+	ps := l.client.plans.Load()
+	dp := ps.plans[ps.def]
 	st := Stats{
-		PerClientRate:  l.cfg.PerClient.RatePerSec,
-		PerClientBurst: l.cfg.PerClient.Burst,
+		PerClientRate:  dp.RatePerSec,
+		PerClientBurst: dp.Burst,
 		NumShards:      l.cfg.NumShards,
+		Routes:         make(map[string]RouteCostStats, len(l.routes)),
+		DefaultPlan:    ps.def,
+		Plans:          ps.plans, // immutable
+		AssignedPlans:  len(ps.clients),
+	}
+	for route, rc := range l.routes {
+		st.Routes[route] = rc.stats()
 	}
 	if l.cfg.Global != nil {
 		st.GlobalRate = l.cfg.Global.RatePerSec
@@ -135,3 +261,31 @@ func (l *Composite) Stats() Stats {
 	}
 	return st
 }
+
+// Snapshot returns the limiter's stats as a wire contract.
+func (l *Composite) Snapshot() contracts.LimiterSnapshot {
+	st := l.Stats()
+	snap := contracts.LimiterSnapshot{
+		PerClientRate:  st.PerClientRate,
+		PerClientBurst: st.PerClientBurst,
+		GlobalRate:     st.GlobalRate,
+		GlobalBurst:    st.GlobalBurst,
+		Routes:         make(map[string]contracts.RouteCostSnapshot, len(st.Routes)),
+		DefaultPlan:    st.DefaultPlan,
+		Plans:          make(map[string]contracts.PlanSnapshot, len(st.Plans)),
+		AssignedPlans:  st.AssignedPlans,
+	}
+	for name, p := range st.Plans {
+		snap.Plans[name] = contracts.PlanSnapshot{RatePerSec: p.RatePerSec, Burst: p.Burst, TTL: p.TTL.String()}
+	}
+	for route, rc := range st.Routes {
+		snap.Routes[route] = contracts.RouteCostSnapshot{
+			Base:     rc.Base,
+			MaxCost:  rc.MaxCost,
+			Requests: rc.Requests,
+			Refused:  rc.Refused,
+			Charged:  rc.Charged,
+		}
+	}
+	return snap
+}
@@ -29,4 +29,10 @@ type Config struct {
 	NumShards       int           // 0 => default (64)
 	CleanupInterval time.Duration // 0 => default (1m)
 	Clock           Clock         // nil => system clock
+	// Costs prices requests by route (RequestMeta.Target) for AllowCost. Unlisted
+	// routes, and Allow, cost 1 token. A request costing more than a bucket's
+	// burst is refused, so keep every route's dearest request within the
+	// smallest burst; New enforces it for the global and initial per-client
+	// bursts, SetPlans for plans.
+	Costs map[string]RouteCost
 }
@@ -0,0 +1,110 @@
+package limiter
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// RouteCost prices the requests of one route in tokens.
+type RouteCost struct {
+	// Base is paid by every request. <= 0 => 1.
+	Base float64
+	// Bands add the Cost of the highest band a command's amount reaches, for
+	// commands exposing AmountCents() (e.g. inbound.TransferCommand).
+	Bands []AmountBand
+}
+
+// AmountBand charges Cost for amounts of at least MinCents.
+type AmountBand struct {
+	MinCents int64
+	Cost     float64
+}
+
+// max returns the most a request on the route can cost.
+func (rc RouteCost) max() float64 {
+	var band float64
+	for _, b := range rc.Bands {
+		band = math.Max(band, b.Cost)
+	}
+	return rc.Base + band
+}
+
+// checkCosts reports the first route whose dearest request would not fit a
+// bucket of burst, so it could never be admitted.
+func checkCosts(routes map[string]*routeCost, burst int) error {
+	for route, rc := range routes {
+		if m := rc.cfg.max(); m > float64(burst) {
+			return fmt.Errorf("burst %d is below the %g tokens %q can charge", burst, m, route)
+		}
+	}
+	return nil
+}
+
+// routeCost is a configured route and its counters.
+type routeCost struct {
+	cfg RouteCost
+
+	mu       sync.Mutex
+	requests int64
+	refused  int64
+	charged  float64 // tokens consumed by admitted requests
+}
+
+func newRouteCost(cfg RouteCost) *routeCost {
+	if cfg.Base <= 0 {
+		cfg.Base = 1
+	}
+	return &routeCost{cfg: cfg}
+}
+
+// of returns the cost of cmd.
+func (r *routeCost) of(cmd hexa_inbound.Command) float64 {
+	cost := r.cfg.Base
+	c, ok := cmd.(interface{ AmountCents() int64 })
+	if !ok {
+		return cost
+	}
+	var band float64
+	var floor int64 = -1
+	for _, b := range r.cfg.Bands {
+		if c.AmountCents() >= b.MinCents && b.MinCents > floor {
+			band, floor = b.Cost, b.MinCents
+		}
+	}
+	return cost + math.Max(band, 0)
+}
+
+func (r *routeCost) record(cost float64, allowed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests++
+	if allowed {
+		r.charged += cost
+	} else {
+		r.refused++
+	}
+}
+
+// RouteCostStats is a route's cost configuration and what it has charged.
+type RouteCostStats struct {
+	Base     float64
+	MaxCost  float64 // Base plus the dearest band
+	Requests int64
+	Refused  int64
+	Charged  float64 // tokens consumed by admitted requests
+}
+
+func (r *routeCost) stats() RouteCostStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return RouteCostStats{
+		Base:     r.cfg.Base,
+		MaxCost:  r.cfg.max(),
+		Requests: r.requests,
+		Refused:  r.refused,
+		Charged:  r.charged,
+	}
+}
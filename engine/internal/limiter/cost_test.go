@@ -0,0 +1,103 @@
+package limiter
+
+import (
+	"strings"
+	"testing"
+)
+
+// transferCmd is a command priced by amount.
+type transferCmd struct{ cents int64 }
+
+func (c transferCmd) AmountCents() int64 { return c.cents }
+
+func newLimiter(t *testing.T, cfg Config) *Composite {
+	t.Helper()
+	l, err := New(t.Context(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(l.Stop)
+	return l
+}
+
+func pricedLimiter(t *testing.T, burst int) *Composite {
+	t.Helper()
+	return newLimiter(t, Config{
+		PerClient: PerClientConfig{RatePerSec: 1, Burst: burst},
+		Clock:     newFakeClock(),
+		Costs: map[string]RouteCost{"POST /transfer": {Base: 1, Bands: []AmountBand{
+			{MinCents: 1_000_000, Cost: 2},
+			{MinCents: 100_000_000, Cost: 9},
+		}}},
+	})
+}
+
+func TestNewRejectsBurstBelowRouteCost(t *testing.T) {
+	costs := map[string]RouteCost{"POST /transfer": {Base: 1, Bands: []AmountBand{{MinCents: 100_000_000, Cost: 9}}}}
+	tests := map[string]struct {
+		cfg  Config
+		want string
+	}{
+		"per-client burst": {
+			cfg:  Config{PerClient: PerClientConfig{RatePerSec: 1, Burst: 5}, Costs: costs},
+			want: "per-client limit",
+		},
+		"global burst": {
+			cfg: Config{
+				PerClient: PerClientConfig{RatePerSec: 1, Burst: 10},
+				Global:    &GlobalConfig{RatePerSec: 100, Burst: 9},
+				Costs:     costs,
+			},
+			want: "global limit",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			l, err := New(t.Context(), tc.cfg)
+			if err == nil {
+				l.Stop()
+				t.Fatal("a 10-token route was accepted")
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("err = %v, want %q", err, tc.want)
+			}
+		})
+	}
+
+	// Unlimited buckets admit any cost.
+	l := newLimiter(t, Config{Global: &GlobalConfig{}, Costs: costs})
+	if d := l.AllowCost("acme", "POST /transfer", transferCmd{cents: 100_000_000}); !d.Allowed {
+		t.Fatalf("unlimited limiter refused a 10-token transfer: %+v", d)
+	}
+}
+
+func TestCostIsDrawnFromTheBucket(t *testing.T) {
+	l := pricedLimiter(t, 10)
+
+	if d := l.AllowCost("acme", "POST /transfer", transferCmd{cents: 1_000_000}); !d.Allowed || d.Remaining != 7 {
+		t.Fatalf("3-token transfer: got %+v, want allowed with 7 remaining", d)
+	}
+	if d := l.AllowCost("acme", "POST /transfer", transferCmd{cents: 100_000_000}); d.Allowed || d.RetryAfter <= 0 {
+		t.Fatalf("10-token transfer on 7 tokens: got %+v, want refused with a Retry-After", d)
+	}
+}
+
+func TestSetPlansRejectsBurstBelowRouteCost(t *testing.T) {
+	l := pricedLimiter(t, 100)
+
+	_, err := l.SetPlans(Plans{Default: "standard", Plans: map[string]PerClientConfig{
+		"standard": {RatePerSec: 50, Burst: 100},
+		"free":     {RatePerSec: 5, Burst: 9},
+	}})
+	if err == nil || !strings.Contains(err.Error(), `plan "free"`) {
+		t.Fatalf("err = %v, want the free plan rejected", err)
+	}
+	if got := l.PlanFor("acme").Plan; got != DefaultPlan {
+		t.Fatalf("plan = %q after a rejected reload, want %q kept", got, DefaultPlan)
+	}
+	if _, err := l.SetPlans(Plans{Default: "free", Plans: map[string]PerClientConfig{
+		"free": {RatePerSec: 5, Burst: 10},
+	}}); err != nil {
+		t.Fatalf("burst equal to the dearest cost rejected: %v", err)
+	}
+}
@@ -0,0 +1,327 @@
+package limiter
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/contracts"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+
+	hexa_inbound "github.com/race-conditioned/hexa/horizon/ports/inbound"
+)
+
+// Compile-time check that *Distributed implements the limiter ports.
+var (
+	_ outbound.CostLimiter = (*Distributed)(nil)
+	_ outbound.PlanLimiter = (*Distributed)(nil)
+)
+
+// DistributedConfig tunes a Distributed limiter.
+type DistributedConfig struct {
+	Store outbound.AtomicKV
+	// Prefix namespaces the limiter's keys in a shared store. "" => "ratelimit:".
+	Prefix string
+	// Timeout bounds each decision's store calls. <= 0 => 50ms.
+	Timeout time.Duration
+	// MaxRetries bounds compare-and-swap retries per key under contention before
+	// the decision falls back. <= 0 => 5.
+	MaxRetries int
+	// Breaker stops calling a failing store: while it is open, decisions fall back
+	// at once instead of waiting for Timeout. nil => none.
+	Breaker outbound.CircuitBreaker
+}
+
+// Distributed enforces the local Composite's limits (plans, global bucket, route
+// costs) across gateway replicas with GCRA over a shared outbound.AtomicKV. When
+// the store fails, decisions fall back to the local Composite, so each replica
+// limits on its own until the store is back. It is safe for concurrent use.
+type Distributed struct {
+	cfg   DistributedConfig
+	local *Composite
+
+	decisions, fallbacks, storeErrors, conflicts atomic.Int64
+}
+
+// NewDistributed creates a Distributed limiter over cfg.Store, falling back to local.
+func NewDistributed(cfg DistributedConfig, local *Composite) *Distributed {
+	if cfg.Prefix == "" {
+		cfg.Prefix = "ratelimit:"
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 50 * time.Millisecond
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 5
+	}
+	return &Distributed{cfg: cfg, local: local}
+}
+
+// errContended ends a decision whose compare-and-swap kept losing. It is a
+// conflict, not a store failure, so a breaker does not count it.
+var errContended = apperr.Conflict("rate limit state contended")
+
+// Allow implements outbound.Limiter.
+func (d *Distributed) Allow(clientID string) outbound.LimitDecision {
+	return d.allow(clientID, 1)
+}
+
+// AllowCost implements outbound.CostLimiter with the local Composite's Config.Costs.
+func (d *Distributed) AllowCost(clientID, route string, cmd hexa_inbound.Command) outbound.LimitDecision {
+	rc, ok := d.local.routes[route]
+	if !ok {
+		return d.allow(clientID, 1)
+	}
+	cost := rc.of(cmd)
+	dec := d.allow(clientID, cost)
+	rc.record(cost, dec.Allowed)
+	return dec
+}
+
+// PlanFor implements outbound.PlanLimiter. Remaining reflects the shared state
+// when the store answers, and the local bucket otherwise.
+func (d *Distributed) PlanFor(clientID string) outbound.ClientPlan {
+	cp := d.local.PlanFor(clientID)
+	if cp.RatePerSec <= 0 {
+		return cp
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), d.cfg.Timeout)
+	defer cancel()
+	v, ok, err := d.cfg.Store.Get(ctx, d.clientKey(clientID))
+	if err != nil {
+		return cp
+	}
+	cp.Tracked, cp.Remaining = ok, cp.Burst
+	if ok {
+		now := d.now()
+		cp.Remaining = newGCRA(cp.RatePerSec, cp.Burst).remaining(max64(decodeTAT(v), now), now)
+	}
+	return cp
+}
+
+func (d *Distributed) allow(clientID string, cost float64) outbound.LimitDecision {
+	d.decisions.Add(1)
+	dec, err := d.guarded(clientID, cost)
+	if err != nil {
+		if apperr.As(err).Code == apperr.CodeInternal {
+			d.storeErrors.Add(1)
+		}
+		d.fallbacks.Add(1)
+		return d.local.allow(clientID, cost)
+	}
+	return dec
+}
+
+// guarded runs the shared decision through the breaker, if any.
+func (d *Distributed) guarded(clientID string, cost float64) (dec outbound.LimitDecision, err error) {
+	if d.cfg.Breaker != nil {
+		done, open := d.cfg.Breaker.Allow()
+		if open != nil {
+			return dec, open
+		}
+		defer func() { done(err) }()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), d.cfg.Timeout)
+	defer cancel()
+	return d.shared(ctx, clientID, cost)
+}
+
+// shared charges the client's key and then the global key. Anything that stops
+// the global charge (a refusal, a store error, contention) refunds the client's
+// charge, so either both are charged or neither (up to a concurrent reader seeing
+// the client charge for a moment).
+func (d *Distributed) shared(ctx context.Context, clientID string, cost float64) (outbound.LimitDecision, error) {
+	_, plan, _ := d.local.client.plans.Load().resolve(clientID)
+	now := d.now()
+
+	dec := outbound.LimitDecision{Allowed: true}
+	var client *gcra
+	if plan.RatePerSec > 0 {
+		client = newGCRA(plan.RatePerSec, plan.Burst)
+		cd, err := d.charge(ctx, d.clientKey(clientID), client, cost, now)
+		if err != nil || !cd.Allowed {
+			return cd, err
+		}
+		dec = cd
+	}
+
+	if g := d.local.cfg.Global; g != nil && g.RatePerSec > 0 {
+		gd, err := d.charge(ctx, d.cfg.Prefix+"global", newGCRA(g.RatePerSec, g.Burst), cost, now)
+		if client != nil && (err != nil || !gd.Allowed) {
+			// ctx may be what failed the global charge, so the refund gets its own.
+			rctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), d.cfg.Timeout)
+			if rerr := d.refund(rctx, d.clientKey(clientID), client, cost, now); err == nil {
+				err = rerr
+			}
+			cancel()
+		}
+		if err != nil {
+			return outbound.LimitDecision{}, err
+		}
+		if !gd.Allowed || gd.Remaining < dec.Remaining || dec.Limit == 0 {
+			return gd, nil
+		}
+	}
+	return dec, nil
+}
+
+// charge applies GCRA at key, retrying lost compare-and-swaps.
+func (d *Distributed) charge(ctx context.Context, key string, g *gcra, cost float64, now int64) (outbound.LimitDecision, error) {
+	inc := g.increment(cost)
+	for range d.cfg.MaxRetries {
+		old, ok, err := d.cfg.Store.Get(ctx, key)
+		if err != nil {
+			return outbound.LimitDecision{}, storeErr(err)
+		}
+		tat := now
+		if ok {
+			tat = max64(decodeTAT(old), now)
+		}
+		if !ok {
+			old = nil
+		}
+
+		next := tat + inc
+		if next-now > g.tolerance {
+			d := outbound.LimitDecision{
+				Limit:     g.burst,
+				Remaining: g.remaining(tat, now),
+				Reset:     time.Duration(tat - now),
+			}
+			if inc <= g.tolerance { // else it never fits: no retry hint
+				d.RetryAfter = time.Duration(next - g.tolerance - now)
+			}
+			return d, nil
+		}
+		// The TTL is on the limiter's clock: the store must expire on the same
+		// one (see kv_store.Config.Clock), or the state can lapse before its TAT.
+		swapped, err := d.cfg.Store.CompareAndSwap(ctx, key, old, encodeTAT(next), time.Duration(next-now))
+		if err != nil {
+			return outbound.LimitDecision{}, storeErr(err)
+		}
+		if swapped {
+			return outbound.LimitDecision{
+				Allowed:   true,
+				Limit:     g.burst,
+				Remaining: g.remaining(next, now),
+				Reset:     time.Duration(next - now),
+			}, nil
+		}
+		d.conflicts.Add(1)
+	}
+	return outbound.LimitDecision{}, errContended
+}
+
+// refund gives back a charge made at now.
+func (d *Distributed) refund(ctx context.Context, key string, g *gcra, cost float64, now int64) error {
+	inc := g.increment(cost)
+	for range d.cfg.MaxRetries {
+		old, ok, err := d.cfg.Store.Get(ctx, key)
+		if err != nil {
+			return storeErr(err)
+		}
+		if !ok {
+			return nil // expired: nothing left to give back
+		}
+		next := max64(decodeTAT(old)-inc, now)
+		// At now the state is spent; the shortest TTL lets it lapse at once.
+		swapped, err := d.cfg.Store.CompareAndSwap(ctx, key, old, encodeTAT(next), time.Duration(max64(next-now, 1)))
+		if err != nil {
+			return storeErr(err)
+		}
+		if swapped {
+			return nil
+		}
+		d.conflicts.Add(1)
+	}
+	return errContended
+}
+
+func (d *Distributed) clientKey(clientID string) string {
+	return d.cfg.Prefix + "client:" + clientID
+}
+
+// now is wall-clock Unix nanoseconds: state is shared across hosts.
+func (d *Distributed) now() int64 { return d.local.clock.Now().UnixNano() }
+
+func storeErr(err error) error {
+	return apperr.Wrap(apperr.CodeInternal, "rate limit store", err)
+}
+
+// gcra holds the Generic Cell Rate Algorithm parameters of one limit, in
+// nanoseconds. The stored state is the theoretical arrival time (TAT): a request
+// is admitted while TAT stays within tolerance of now.
+type gcra struct {
+	interval  float64 // between tokens
+	tolerance int64   // interval × burst
+	burst     int
+}
+
+func newGCRA(rate float64, burst int) *gcra {
+	burst = max(1, burst)
+	interval := float64(time.Second) / rate
+	return &gcra{interval: interval, tolerance: int64(interval * float64(burst)), burst: burst}
+}
+
+// increment is the TAT advance for cost. Above the burst it exceeds tolerance,
+// so the request is refused like in tokenBucket.
+func (g *gcra) increment(cost float64) int64 {
+	return int64(math.Ceil(g.interval * cost))
+}
+
+// remaining is the whole tokens left at now for tat.
+func (g *gcra) remaining(tat, now int64) int {
+	return max(0, int(float64(g.tolerance-(tat-now))/g.interval))
+}
+
+func encodeTAT(tat int64) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(tat))
+}
+
+func decodeTAT(b []byte) int64 {
+	if len(b) != 8 {
+		return 0 // foreign or corrupt value: start over
+	}
+	return int64(binary.BigEndian.Uint64(b))
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// DistributedStats is a point-in-time view of the shared decisions.
+type DistributedStats struct {
+	Decisions   int64
+	Fallbacks   int64 // decided by the local Composite
+	StoreErrors int64 // store calls that failed (not counting an open breaker)
+	Conflicts   int64 // lost compare-and-swaps, retried
+}
+
+// Stats returns the local Composite's stats and the shared decision counters.
+func (d *Distributed) Stats() (Stats, DistributedStats) {
+	return d.local.Stats(), DistributedStats{
+		Decisions:   d.decisions.Load(),
+		Fallbacks:   d.fallbacks.Load(),
+		StoreErrors: d.storeErrors.Load(),
+		Conflicts:   d.conflicts.Load(),
+	}
+}
+
+// Snapshot returns the limiter's stats as a wire contract.
+func (d *Distributed) Snapshot() contracts.LimiterSnapshot {
+	snap := d.local.Snapshot()
+	_, st := d.Stats()
+	snap.Distributed = &contracts.DistributedLimiterSnapshot{
+		Decisions:   st.Decisions,
+		Fallbacks:   st.Fallbacks,
+		StoreErrors: st.StoreErrors,
+		Conflicts:   st.Conflicts,
+	}
+	return snap
+}
@@ -0,0 +1,242 @@
+package limiter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/kv_store"
+)
+
+// fakeClock is a Clock (for both the limiter and kv_store) that moves only when told.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock { return &fakeClock{now: time.Unix(1_700_000_000, 0)} }
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// faultyKV wraps a store and fails the calls that fail() picks.
+type faultyKV struct {
+	outbound.AtomicKV
+	fail func(key string) error
+	// steal, when set, rewrites the key just before each compare-and-swap, as a
+	// concurrent replica would.
+	steal bool
+}
+
+func (f *faultyKV) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	if err := f.fail(key); err != nil {
+		return nil, false, err
+	}
+	return f.AtomicKV.Get(ctx, key)
+}
+
+func (f *faultyKV) CompareAndSwap(ctx context.Context, key string, old, value []byte, ttl time.Duration) (bool, error) {
+	if err := f.fail(key); err != nil {
+		return false, err
+	}
+	if f.steal {
+		cur, _, _ := f.AtomicKV.Get(ctx, key)
+		if _, err := f.AtomicKV.CompareAndSwap(ctx, key, cur, encodeTAT(decodeTAT(cur)+1), time.Minute); err != nil {
+			return false, err
+		}
+	}
+	return f.AtomicKV.CompareAndSwap(ctx, key, old, value, ttl)
+}
+
+func never(string) error { return nil }
+
+// replica builds one gateway replica's limiter over store.
+func replica(t *testing.T, clk Clock, store outbound.AtomicKV, cfg Config, dcfg DistributedConfig) *Distributed {
+	t.Helper()
+	cfg.Clock = clk
+	local := newLimiter(t, cfg)
+	dcfg.Store = store
+	return NewDistributed(dcfg, local)
+}
+
+func memoryKV(t *testing.T, clk *fakeClock) *kv_store.Memory {
+	t.Helper()
+	m := kv_store.NewMemory(t.Context(), kv_store.Config{Clock: clk})
+	t.Cleanup(m.Stop)
+	return m
+}
+
+func TestDistributedGCRASharesBurstAcrossReplicas(t *testing.T) {
+	clk := newFakeClock()
+	store := memoryKV(t, clk)
+	cfg := Config{PerClient: PerClientConfig{RatePerSec: 10, Burst: 5}}
+	a := replica(t, clk, store, cfg, DistributedConfig{})
+	b := replica(t, clk, store, cfg, DistributedConfig{})
+
+	for i := range 5 {
+		r := a
+		if i%2 == 1 {
+			r = b
+		}
+		if d := r.Allow("acme"); !d.Allowed || d.Remaining != 4-i {
+			t.Fatalf("request %d: got %+v, want allowed with %d remaining", i, d, 4-i)
+		}
+	}
+	d := b.Allow("acme")
+	if d.Allowed {
+		t.Fatalf("request past the shared burst was admitted: %+v", d)
+	}
+	if d.RetryAfter != 100*time.Millisecond {
+		t.Fatalf("RetryAfter = %s, want one interval (100ms)", d.RetryAfter)
+	}
+
+	clk.Advance(100 * time.Millisecond)
+	if d := a.Allow("acme"); !d.Allowed {
+		t.Fatalf("request after one interval was refused: %+v", d)
+	}
+	if d := b.Allow("acme"); d.Allowed {
+		t.Fatalf("only one token should have refilled: %+v", d)
+	}
+	if _, st := a.Stats(); st.Fallbacks != 0 {
+		t.Fatalf("fallbacks = %d, want 0", st.Fallbacks)
+	}
+}
+
+func TestDistributedStateExpiresOnTheSharedClock(t *testing.T) {
+	clk := newFakeClock()
+	store := memoryKV(t, clk)
+	d := replica(t, clk, store, Config{PerClient: PerClientConfig{RatePerSec: 10, Burst: 5}}, DistributedConfig{})
+
+	d.Allow("acme")
+	time.Sleep(5 * time.Millisecond) // wall time must not matter
+	if _, ok, _ := store.Get(t.Context(), d.clientKey("acme")); !ok {
+		t.Fatal("state expired while the shared clock stood still")
+	}
+	clk.Advance(100 * time.Millisecond)
+	if _, ok, _ := store.Get(t.Context(), d.clientKey("acme")); ok {
+		t.Fatal("state outlived its TAT on the shared clock")
+	}
+}
+
+func TestDistributedConcurrentChargesNeverOverAdmit(t *testing.T) {
+	clk := newFakeClock()
+	store := memoryKV(t, clk)
+	cfg := Config{PerClient: PerClientConfig{RatePerSec: 1, Burst: 20}}
+	// Enough retries that contention never falls back to the (per-replica) local limits.
+	dcfg := DistributedConfig{MaxRetries: 10_000, Timeout: time.Minute}
+	replicas := []*Distributed{replica(t, clk, store, cfg, dcfg), replica(t, clk, store, cfg, dcfg)}
+
+	var admitted atomic.Int64
+	var wg sync.WaitGroup
+	for i := range 32 {
+		wg.Go(func() {
+			for range 10 {
+				if replicas[i%2].Allow("acme").Allowed {
+					admitted.Add(1)
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	if got := admitted.Load(); got != 20 {
+		t.Fatalf("admitted %d, want exactly the burst (20)", got)
+	}
+	for _, r := range replicas {
+		if _, st := r.Stats(); st.Fallbacks != 0 {
+			t.Fatalf("fallbacks = %d, want 0", st.Fallbacks)
+		}
+	}
+}
+
+func TestDistributedLostSwapsRetryThenFallBack(t *testing.T) {
+	clk := newFakeClock()
+	store := &faultyKV{AtomicKV: memoryKV(t, clk), fail: never, steal: true}
+	d := replica(t, clk, store, Config{PerClient: PerClientConfig{RatePerSec: 10, Burst: 5}}, DistributedConfig{MaxRetries: 3})
+
+	if dec := d.Allow("acme"); !dec.Allowed {
+		t.Fatalf("fallback should decide locally and admit: %+v", dec)
+	}
+	_, st := d.Stats()
+	if st.Conflicts != 3 || st.Fallbacks != 1 {
+		t.Fatalf("conflicts = %d, fallbacks = %d; want 3 and 1", st.Conflicts, st.Fallbacks)
+	}
+	if st.StoreErrors != 0 {
+		t.Fatalf("contention counted as %d store errors", st.StoreErrors)
+	}
+}
+
+func TestDistributedRefundsClientOnGlobalRefusal(t *testing.T) {
+	clk := newFakeClock()
+	store := memoryKV(t, clk)
+	d := replica(t, clk, store, Config{
+		PerClient: PerClientConfig{RatePerSec: 10, Burst: 5},
+		Global:    &GlobalConfig{RatePerSec: 1, Burst: 1},
+	}, DistributedConfig{})
+
+	if dec := d.Allow("acme"); !dec.Allowed {
+		t.Fatalf("first request refused: %+v", dec)
+	}
+	if dec := d.Allow("acme"); dec.Allowed {
+		t.Fatalf("global burst exceeded: %+v", dec)
+	}
+	if got := d.PlanFor("acme").Remaining; got != 4 {
+		t.Fatalf("client remaining = %d, want 4 (refused charge refunded)", got)
+	}
+}
+
+func TestDistributedRefundsClientOnGlobalStoreError(t *testing.T) {
+	clk := newFakeClock()
+	store := &faultyKV{AtomicKV: memoryKV(t, clk), fail: func(key string) error {
+		if strings.HasSuffix(key, "global") {
+			return errors.New("connection reset")
+		}
+		return nil
+	}}
+	d := replica(t, clk, store, Config{
+		PerClient: PerClientConfig{RatePerSec: 10, Burst: 5},
+		Global:    &GlobalConfig{RatePerSec: 100, Burst: 100},
+	}, DistributedConfig{})
+
+	if dec := d.Allow("acme"); !dec.Allowed {
+		t.Fatalf("fallback should decide locally and admit: %+v", dec)
+	}
+	if got := d.PlanFor("acme").Remaining; got != 5 {
+		t.Fatalf("client remaining = %d, want 5 (charge refunded after the global error)", got)
+	}
+	if _, st := d.Stats(); st.Fallbacks != 1 || st.StoreErrors != 1 {
+		t.Fatalf("fallbacks = %d, store errors = %d; want 1 and 1", st.Fallbacks, st.StoreErrors)
+	}
+}
+
+func TestDistributedFallsBackToLocalLimits(t *testing.T) {
+	clk := newFakeClock()
+	store := &faultyKV{AtomicKV: memoryKV(t, clk), fail: func(string) error { return errors.New("unreachable") }}
+	d := replica(t, clk, store, Config{PerClient: PerClientConfig{RatePerSec: 1, Burst: 3}}, DistributedConfig{})
+
+	for i := range 3 {
+		if dec := d.Allow("acme"); !dec.Allowed {
+			t.Fatalf("request %d refused by the local fallback: %+v", i, dec)
+		}
+	}
+	if dec := d.Allow("acme"); dec.Allowed {
+		t.Fatalf("local fallback did not enforce the burst: %+v", dec)
+	}
+	if _, st := d.Stats(); st.Fallbacks != 4 || st.StoreErrors != 4 {
+		t.Fatalf("fallbacks = %d, store errors = %d; want 4 and 4", st.Fallbacks, st.StoreErrors)
+	}
+}
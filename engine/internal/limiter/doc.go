@@ -3,9 +3,17 @@
 // outbound.Limiter interface used by the policy middleware.
 //
 // Design goals:
-//   - Non-blocking (boolean Allow) with deterministic, lazy refill.
-//   - Per-client isolation and fairness via sharded maps.
+//   - Non-blocking Allow with deterministic, lazy refill. The decision reports
+//     the tightest bucket's limit, remaining tokens and reset, and when refused
+//     how long until a token is available, for Retry-After and RateLimit-*.
+//   - Weighted costs: routes declare a base cost plus amount bands; both
+//     buckets are charged the full cost atomically or not at all, and plans
+//     whose burst cannot hold a route's dearest request are rejected.
+//   - Per-client isolation and fairness via sharded maps, with named plans
+//     assigned per client and swapped on reload without resetting buckets.
 //   - Optional global bucket to cap aggregate QPS.
+//   - Distributed mode: the same limits shared across replicas with GCRA over an
+//     outbound.AtomicKV, falling back to the local Composite when the store fails.
 //   - Monotonic time, burst support, safe under high contention.
 //   - Low heap churn & passive cleanup of inactive clients.
 //
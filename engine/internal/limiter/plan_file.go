@@ -0,0 +1,121 @@
+package limiter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"fintech-capstone/m/v2/internal/platform"
+)
+
+// planFile is the JSON layout of a plans file:
+//
+//	{
+//	  "default": "standard",
+//	  "plans": {
+//	    "free":     {"rate_per_sec": 5,  "burst": 10,  "ttl": "10m"},
+//	    "standard": {"rate_per_sec": 50, "burst": 100, "ttl": "15m"}
+//	  },
+//	  "clients": {"acme-payouts": "partner"}
+//	}
+type planFile struct {
+	Default string              `json:"default"`
+	Plans   map[string]planSpec `json:"plans"`
+	Clients map[string]string   `json:"clients"`
+}
+
+type planSpec struct {
+	RatePerSec    float64 `json:"rate_per_sec"` // <= 0 => unlimited
+	Burst         int     `json:"burst"`
+	InitialTokens float64 `json:"initial_tokens"` // <= 0 => burst
+	TTL           string  `json:"ttl"`            // Go duration; "" => 10m
+}
+
+// LoadPlans reads and validates a plans file.
+func LoadPlans(path string) (Plans, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Plans{}, fmt.Errorf("read plans: %w", err)
+	}
+	var f planFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return Plans{}, fmt.Errorf("decode plans: %w", err)
+	}
+
+	p := Plans{Default: f.Default, Plans: make(map[string]PerClientConfig, len(f.Plans)), Clients: f.Clients}
+	for name, spec := range f.Plans {
+		cfg := PerClientConfig{RatePerSec: spec.RatePerSec, Burst: spec.Burst, InitialTokens: spec.InitialTokens}
+		if spec.TTL != "" {
+			if cfg.TTL, err = time.ParseDuration(spec.TTL); err != nil {
+				return Plans{}, fmt.Errorf("plan %q: ttl: %w", name, err)
+			}
+		}
+		p.Plans[name] = cfg
+	}
+	if err := p.validate(); err != nil {
+		return Plans{}, fmt.Errorf("plans: %w", err)
+	}
+	return p, nil
+}
+
+// WatchPlans applies the plans file at path to l whenever it changes (checked
+// every interval; <= 0 => 5s) or a value arrives on reload (e.g. SIGHUP), until
+// ctx is done. It does not load the file up front. A file that fails to load is
+// logged and the plans in effect are kept.
+func WatchPlans(ctx context.Context, l *Composite, path string, interval time.Duration, reload <-chan os.Signal, logger platform.Logger) {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	last := fileVersion(path)
+	apply := func(reason string) {
+		last = fileVersion(path)
+		p, err := LoadPlans(path)
+		if err != nil {
+			logger.Error(err, platform.Field{Key: "component", Value: "limiter"}, platform.Field{Key: "path", Value: path})
+			return
+		}
+		moved, err := l.SetPlans(p)
+		if err != nil {
+			logger.Error(err, platform.Field{Key: "component", Value: "limiter"}, platform.Field{Key: "path", Value: path})
+			return
+		}
+		logger.Info("rate limit plans reloaded",
+			platform.Field{Key: "reason", Value: reason},
+			platform.Field{Key: "plans", Value: len(p.Plans)},
+			platform.Field{Key: "clients", Value: len(p.Clients)},
+			platform.Field{Key: "moved", Value: moved},
+		)
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-reload:
+			apply("signal")
+		case <-t.C:
+			if fileVersion(path) != last {
+				apply("file changed")
+			}
+		}
+	}
+}
+
+// fileStamp identifies a file's content by size and modification time; the zero
+// value means it is missing.
+type fileStamp struct {
+	size int64
+	mod  time.Time
+}
+
+func fileVersion(path string) fileStamp {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{size: fi.Size(), mod: fi.ModTime()}
+}
@@ -0,0 +1,170 @@
+package limiter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"fintech-capstone/m/v2/internal/platform"
+)
+
+// eventLogger reports each reload outcome ("reloaded" or the error) on a channel.
+type eventLogger struct{ events chan string }
+
+func newEventLogger() *eventLogger { return &eventLogger{events: make(chan string, 16)} }
+
+func (l *eventLogger) Debug(string, ...platform.Field) {}
+func (l *eventLogger) Info(msg string, _ ...platform.Field) {
+	if msg == "rate limit plans reloaded" {
+		l.events <- "reloaded"
+	}
+}
+func (l *eventLogger) Warn(string, ...platform.Field)       {}
+func (l *eventLogger) Error(err error, _ ...platform.Field) { l.events <- err.Error() }
+func (l *eventLogger) With(...platform.Field) platform.Logger {
+	return l
+}
+func (l *eventLogger) Fatal(err error, _ ...platform.Field) { l.events <- err.Error() }
+
+func (l *eventLogger) next(t *testing.T) string {
+	t.Helper()
+	select {
+	case ev := <-l.events:
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reload within 5s")
+		return ""
+	}
+}
+
+func writePlans(t *testing.T, path, body string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadPlans(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plans.json")
+	writePlans(t, path, `{
+		"default": "standard",
+		"plans": {
+			"free":     {"rate_per_sec": 5,  "burst": 10, "ttl": "10m"},
+			"standard": {"rate_per_sec": 50, "burst": 100, "initial_tokens": 20}
+		},
+		"clients": {"acme": "free"}
+	}`)
+
+	p, err := LoadPlans(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Default != "standard" || p.Clients["acme"] != "free" {
+		t.Fatalf("default/clients = %q/%v", p.Default, p.Clients)
+	}
+	if got := p.Plans["free"]; got.RatePerSec != 5 || got.Burst != 10 || got.TTL != 10*time.Minute {
+		t.Fatalf("free = %+v", got)
+	}
+	if got := p.Plans["standard"]; got.InitialTokens != 20 || got.TTL != 0 {
+		t.Fatalf("standard = %+v, want initial 20 and the default TTL left to the limiter", got)
+	}
+}
+
+func TestLoadPlansRejectsInvalidFiles(t *testing.T) {
+	for name, tc := range map[string]struct{ body, want string }{
+		"json":         {`{"default":`, "decode plans"},
+		"ttl":          {`{"default": "a", "plans": {"a": {"rate_per_sec": 1, "burst": 1, "ttl": "soon"}}}`, `plan "a": ttl`},
+		"default":      {`{"default": "gold", "plans": {"a": {"rate_per_sec": 1, "burst": 1}}}`, `default plan "gold"`},
+		"client plan":  {`{"default": "a", "plans": {"a": {"rate_per_sec": 1, "burst": 1}}, "clients": {"acme": "gold"}}`, `client "acme"`},
+		"zero burst":   {`{"default": "a", "plans": {"a": {"rate_per_sec": 1}}}`, "burst must be >= 1"},
+		"missing file": {"", "read plans"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "plans.json")
+			if tc.body != "" {
+				writePlans(t, path, tc.body)
+			}
+			if _, err := LoadPlans(path); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("err = %v, want it to mention %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestWatchPlansReloadKeepsBucketState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plans.json")
+	writePlans(t, path, `{"default": "standard", "plans": {"standard": {"rate_per_sec": 1, "burst": 10}}}`)
+	l := newLimiter(t, Config{PerClient: PerClientConfig{RatePerSec: 1, Burst: 10}, Clock: newFakeClock()})
+	p, err := LoadPlans(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.SetPlans(p); err != nil {
+		t.Fatal(err)
+	}
+	for range 4 {
+		l.Allow("acme")
+	}
+
+	logger := newEventLogger()
+	reload := make(chan os.Signal, 1)
+	go WatchPlans(t.Context(), l, path, time.Hour, reload, logger)
+
+	// A bigger burst keeps the 6 tokens acme has left.
+	writePlans(t, path, `{"default": "standard", "plans": {"standard": {"rate_per_sec": 1, "burst": 20}}}`)
+	reload <- os.Interrupt
+	if ev := logger.next(t); ev != "reloaded" {
+		t.Fatalf("reload: %s", ev)
+	}
+	if cp := l.PlanFor("acme"); cp.Burst != 20 || cp.Remaining != 6 {
+		t.Fatalf("after growing the burst: %+v, want burst 20 with 6 remaining", cp)
+	}
+
+	// A smaller one caps them at the new burst.
+	writePlans(t, path, `{"default": "standard", "plans": {"standard": {"rate_per_sec": 1, "burst": 3}}}`)
+	reload <- os.Interrupt
+	logger.next(t)
+	if cp := l.PlanFor("acme"); cp.Burst != 3 || cp.Remaining != 3 {
+		t.Fatalf("after shrinking the burst: %+v, want burst 3 with 3 remaining", cp)
+	}
+
+	// A broken file is logged and the plans in effect stay.
+	writePlans(t, path, `{"default": "gold", "plans": {}}`)
+	reload <- os.Interrupt
+	if ev := logger.next(t); !strings.Contains(ev, `default plan "gold"`) {
+		t.Fatalf("broken file: %s", ev)
+	}
+	if cp := l.PlanFor("acme"); cp.Burst != 3 || cp.Plan != "standard" {
+		t.Fatalf("after a broken file: %+v, want the previous plans kept", cp)
+	}
+}
+
+func TestWatchPlansPicksUpFileChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plans.json")
+	writePlans(t, path, `{"default": "standard", "plans": {"standard": {"rate_per_sec": 1, "burst": 10}}}`)
+	l := newLimiter(t, Config{PerClient: PerClientConfig{RatePerSec: 1, Burst: 10}, Clock: newFakeClock()})
+
+	logger := newEventLogger()
+	go WatchPlans(t.Context(), l, path, 10*time.Millisecond, nil, logger)
+
+	// Not loaded up front: the file is only applied once it changes. The watcher
+	// stamps the file when it starts, so keep changing it until a reload lands.
+	changed := `{"default": "standard", "plans": {"standard": {"rate_per_sec": 1, "burst": 10}, "free": {"rate_per_sec": 1, "burst": 5}}, "clients": {"acme": "free"}}`
+	deadline := time.Now().Add(5 * time.Second)
+	for ev := ""; ev != "reloaded"; {
+		if time.Now().After(deadline) {
+			t.Fatal("file change not picked up within 5s")
+		}
+		changed += " "
+		writePlans(t, path, changed)
+		select {
+		case ev = <-logger.events:
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	if cp := l.PlanFor("acme"); cp.Plan != "free" || !cp.Assigned || cp.Burst != 5 {
+		t.Fatalf("acme = %+v, want the assigned free plan", cp)
+	}
+}
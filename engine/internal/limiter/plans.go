@@ -0,0 +1,70 @@
+package limiter
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultPlan names the plan built from Config.PerClient until SetPlans is called.
+const DefaultPlan = "default"
+
+// Plans names per-client limits (e.g. free, standard, partner, internal) and
+// assigns them to client IDs. Clients without an assignment get Default.
+type Plans struct {
+	Default string
+	Plans   map[string]PerClientConfig
+	Clients map[string]string // client ID => plan name
+}
+
+// validate checks that every referenced plan exists and can admit a request.
+func (p Plans) validate() error {
+	if _, ok := p.Plans[p.Default]; !ok {
+		return fmt.Errorf("default plan %q is not defined", p.Default)
+	}
+	for name, cfg := range p.Plans {
+		if cfg.RatePerSec > 0 && cfg.Burst < 1 {
+			return fmt.Errorf("plan %q: burst must be >= 1 when rate_per_sec > 0", name)
+		}
+	}
+	for client, name := range p.Clients {
+		if _, ok := p.Plans[name]; !ok {
+			return fmt.Errorf("client %q: plan %q is not defined", client, name)
+		}
+	}
+	return nil
+}
+
+// planSet is an immutable, normalised Plans. It is swapped whole on reload.
+type planSet struct {
+	def     string
+	plans   map[string]PerClientConfig
+	clients map[string]string
+}
+
+func newPlanSet(p Plans) *planSet {
+	ps := &planSet{
+		def:     p.Default,
+		plans:   make(map[string]PerClientConfig, len(p.Plans)),
+		clients: make(map[string]string, len(p.Clients)),
+	}
+	for name, cfg := range p.Plans {
+		if cfg.TTL <= 0 {
+			cfg.TTL = 10 * time.Minute
+		}
+		ps.plans[name] = cfg
+	}
+	for client, name := range p.Clients {
+		ps.clients[client] = name
+	}
+	return ps
+}
+
+// resolve returns the plan in effect for clientID and whether it was assigned
+// rather than the default.
+func (ps *planSet) resolve(clientID string) (name string, cfg PerClientConfig, assigned bool) {
+	name, assigned = ps.clients[clientID]
+	if !assigned {
+		name = ps.def
+	}
+	return name, ps.plans[name], assigned
+}
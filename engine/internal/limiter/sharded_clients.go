@@ -3,6 +3,7 @@ package limiter
 import (
 	"hash/fnv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -12,6 +13,9 @@ type clientBucket struct {
 	// updated on every Allow() call (shard lock not required to read/write);
 	// guarded by the bucket's own mutex for consistency with tokens/lastRefill.
 	lastSeen time.Time
+	// plan and ttl follow the client's plan across reloads; guarded by b.mu.
+	plan string
+	ttl  time.Duration
 }
 
 type shard struct {
@@ -22,10 +26,10 @@ type shard struct {
 type clientShardSet struct {
 	shards []shard
 	clock  Clock
-	cfg    PerClientConfig
+	plans  atomic.Pointer[planSet]
 }
 
-func newClientShardSet(n int, clock Clock, cfg PerClientConfig) *clientShardSet {
+func newClientShardSet(n int, clock Clock, plans *planSet) *clientShardSet {
 	if n <= 0 {
 		n = 64
 	}
@@ -33,11 +37,12 @@ func newClientShardSet(n int, clock Clock, cfg PerClientConfig) *clientShardSet
 	for i := range s {
 		s[i].data = make(map[string]*clientBucket, 256)
 	}
-	return &clientShardSet{
+	cs := &clientShardSet{
 		shards: s,
 		clock:  clock,
-		cfg:    cfg,
 	}
+	cs.plans.Store(plans)
+	return cs
 }
 
 func (cs *clientShardSet) getShard(key string) *shard {
@@ -67,13 +72,12 @@ func (cs *clientShardSet) getOrCreate(clientID string) *clientBucket {
 	sh.mu.Lock()
 	cb = sh.data[clientID]
 	if cb == nil {
-		init := cs.cfg.InitialTokens
-		if init <= 0 {
-			init = float64(max(1, cs.cfg.Burst))
-		}
+		name, cfg, _ := cs.plans.Load().resolve(clientID)
 		cb = &clientBucket{
-			b:        newTokenBucket(now, cs.cfg.RatePerSec, cs.cfg.Burst, init),
+			b:        newTokenBucket(now, cfg.RatePerSec, cfg.Burst, initialTokens(cfg)),
 			lastSeen: now,
+			plan:     name,
+			ttl:      cfg.TTL,
 		}
 		sh.data[clientID] = cb
 	}
@@ -81,18 +85,54 @@ func (cs *clientShardSet) getOrCreate(clientID string) *clientBucket {
 	return cb
 }
 
-// cleanup evicts idle client buckets across shards.
-func (cs *clientShardSet) cleanup(ttl time.Duration) (evicted int) {
-	if ttl <= 0 {
-		ttl = 10 * time.Minute
+// setPlans swaps in ps and moves every tracked client onto its plan in place:
+// tokens already earned or spent carry over, capped at the new burst.
+func (cs *clientShardSet) setPlans(ps *planSet) (moved int) {
+	cs.plans.Store(ps)
+	now := cs.clock.Now()
+	for i := range cs.shards {
+		sh := &cs.shards[i]
+		sh.mu.RLock()
+		for id, cb := range sh.data {
+			name, cfg, _ := ps.resolve(id)
+			cb.b.mu.Lock()
+			if cb.plan != name || cb.b.rate != cfg.RatePerSec || cb.b.burst != float64(max(1, cfg.Burst)) {
+				cb.b.reconfigure(now, cfg.RatePerSec, cfg.Burst, initialTokens(cfg))
+				moved++
+			}
+			cb.plan, cb.ttl = name, cfg.TTL
+			cb.b.mu.Unlock()
+		}
+		sh.mu.RUnlock()
+	}
+	return moved
+}
+
+// lookup returns the client's bucket if it is tracked.
+func (cs *clientShardSet) lookup(clientID string) *clientBucket {
+	sh := cs.getShard(clientID)
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+	return sh.data[clientID]
+}
+
+// initialTokens seeds a new bucket for cfg.
+func initialTokens(cfg PerClientConfig) float64 {
+	if cfg.InitialTokens <= 0 {
+		return float64(max(1, cfg.Burst))
 	}
+	return cfg.InitialTokens
+}
+
+// cleanup evicts client buckets idle for longer than their plan's TTL.
+func (cs *clientShardSet) cleanup() (evicted int) {
 	now := cs.clock.Now()
 	for i := range cs.shards {
 		sh := &cs.shards[i]
 		sh.mu.Lock()
 		for id, cb := range sh.data {
 			cb.b.mu.Lock()
-			ls := cb.lastSeen
+			ls, ttl := cb.lastSeen, cb.ttl
 			cb.b.mu.Unlock()
 			if now.Sub(ls) >= ttl {
 				delete(sh.data, id)
@@ -75,6 +75,45 @@ func (b *tokenBucket) consumeNoCheck(cost float64) {
 	}
 }
 
+// unlimited reports whether the bucket never refuses.
+func (b *tokenBucket) unlimited() bool {
+	return b.rate <= 0 || math.IsInf(b.tokens, 1)
+}
+
+// reconfigure applies a new rate and burst, keeping the tokens the bucket holds
+// (capped at the new burst). A bucket leaving unlimited starts from initial.
+func (b *tokenBucket) reconfigure(now time.Time, rate float64, burst int, initial float64) {
+	b.refill(now)
+	wasUnlimited := b.unlimited()
+	b.rate, b.burst = rate, float64(max(1, burst))
+	switch {
+	case rate <= 0:
+		b.tokens = math.Inf(1)
+	case wasUnlimited:
+		b.tokens = clampInit(initial, burst)
+	default:
+		b.tokens = math.Min(b.tokens, b.burst)
+	}
+	b.lastRefill = now
+}
+
+// wait returns how long until the bucket holds cost tokens, or 0 when it never
+// will (cost above the burst). Call after refill.
+func (b *tokenBucket) wait(cost float64) time.Duration {
+	if b.unlimited() || b.tokens >= cost || cost > b.burst {
+		return 0
+	}
+	return seconds((cost - b.tokens) / b.rate)
+}
+
+// untilFull returns how long until the bucket is back at burst. Call after refill.
+func (b *tokenBucket) untilFull() time.Duration {
+	if b.unlimited() || b.tokens >= b.burst {
+		return 0
+	}
+	return seconds((b.burst - b.tokens) / b.rate)
+}
+
 // take tries to consume 'cost' token(s) atomically.
 func (b *tokenBucket) take(now time.Time, cost float64) bool {
 	b.refill(now)
@@ -1,5 +1,15 @@
 package limiter
 
+import (
+	"math"
+	"time"
+)
+
+// seconds converts s to a Duration, rounded up so callers never retry early.
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
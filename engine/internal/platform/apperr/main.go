@@ -1,6 +1,11 @@
 // package apper standardises the error definition across the application.
 package apperr
 
+import (
+	"errors"
+	"time"
+)
+
 // Code defines the type for error codes for consistent mapping to transport layers.
 type Code int
 
@@ -13,6 +18,10 @@ const (
 	CodePayloadTooLarge
 	CodeConflict
 	CodeInternal
+	CodeOverloaded
+	// CodeInProgress: the same request (by idempotency key) is still executing.
+	// Retrying after the hint is safe and usually replays its result.
+	CodeInProgress
 )
 
 // Error represents a standard application error with a code and message.
@@ -20,6 +29,15 @@ type Error struct {
 	Code Code
 	Msg  string
 	Err  error // optional wrap
+	// RetryAfter hints when a retry may succeed (shed, overloaded, rate limited).
+	// Zero means no hint; transports set Retry-After / RetryInfo only when present.
+	RetryAfter time.Duration
+	// Transient marks a failure the same request may not hit again (lock timeout,
+	// leader change, flaky downstream). Only idempotent requests may be retried.
+	Transient bool
+	// Replayed marks a failure answered from the idempotency store rather than
+	// executed again; transports flag it like a replayed result.
+	Replayed bool
 }
 
 // Error implements the error interface.
@@ -28,12 +46,49 @@ func (e *Error) Error() string { return e.Msg }
 // Wrap wraps an existing error with a Code and message.
 func Wrap(code Code, msg string, err error) *Error { return &Error{Code: code, Msg: msg, Err: err} }
 
-// As converts a generic error to an *Error.
+// WithRetryAfter returns a copy of e carrying a retry hint.
+func (e *Error) WithRetryAfter(d time.Duration) *Error {
+	c := *e
+	c.RetryAfter = d
+	return &c
+}
+
+// Replay returns a copy of e for replaying a stored outcome: code and message are
+// kept, so transports answer with the same status; the wrapped cause and retry hint
+// are dropped.
+func (e *Error) Replay() *Error {
+	return &Error{Code: e.Code, Msg: e.Msg, Transient: e.Transient, Replayed: true}
+}
+
+// IsTerminal reports whether err is a final answer for the request: executing it
+// again would fail the same way. Timeouts, rate limiting, overload, internal and
+// Transient failures are not terminal.
+func IsTerminal(err error) bool {
+	e := As(err)
+	if e == nil || e.Transient {
+		return false
+	}
+	switch e.Code {
+	case CodeTimeout, CodeRateLimited, CodeOverloaded, CodeInternal, CodeInProgress:
+		return false
+	}
+	return true
+}
+
+// IsTransient reports whether err, or an error it wraps, is marked Transient.
+func IsTransient(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Transient
+}
+
+// As converts a generic error to an *Error, unwrapping to the first *Error in
+// err's chain (e.g. one wrapped with fmt.Errorf("...: %w", err)).
 func As(err error) *Error {
 	if err == nil {
 		return nil
 	}
-	if e, ok := err.(*Error); ok {
+	var e *Error
+	if errors.As(err, &e) {
 		return e
 	}
 	return &Error{Code: CodeInternal, Msg: err.Error(), Err: err}
@@ -43,6 +98,14 @@ func As(err error) *Error {
 func Invalid(msg string) *Error         { return &Error{Code: CodeInvalid, Msg: msg} }
 func RateLimited(msg string) *Error     { return &Error{Code: CodeRateLimited, Msg: msg} }
 func Timeout(msg string) *Error         { return &Error{Code: CodeTimeout, Msg: msg} }
+func NotFound(msg string) *Error        { return &Error{Code: CodeNotFound, Msg: msg} }
 func Conflict(msg string) *Error        { return &Error{Code: CodeConflict, Msg: msg} }
 func Internal(msg string) *Error        { return &Error{Code: CodeInternal, Msg: msg} }
 func PayloadTooLarge(msg string) *Error { return &Error{Code: CodePayloadTooLarge, Msg: msg} }
+func Overloaded(msg string) *Error      { return &Error{Code: CodeOverloaded, Msg: msg} }
+func InProgress(msg string) *Error      { return &Error{Code: CodeInProgress, Msg: msg} }
+
+// Transient wraps a retryable internal failure, e.g. a storage lock timeout.
+func Transient(msg string, err error) *Error {
+	return &Error{Code: CodeInternal, Msg: msg, Err: err, Transient: true}
+}
@@ -0,0 +1,22 @@
+// Package backoff holds the retry delay shared by the adapters that retry on
+// their own schedule (the retry budget, webhook deliveries).
+package backoff
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// FullJitter returns a full-jitter delay for the given attempt (1 for the first
+// retry): uniform in [0, min(max, base*2^(attempt-1))). Spreading retries over
+// the whole window keeps clients that failed together from retrying together.
+// max must be > 0.
+func FullJitter(attempt int, base, max time.Duration) time.Duration {
+	ceil := max
+	if shift := attempt - 1; shift >= 0 && shift < 32 {
+		if d := base << shift; d > 0 && d < ceil {
+			ceil = d
+		}
+	}
+	return time.Duration(rand.Int64N(int64(ceil)))
+}
@@ -0,0 +1,28 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFullJitterStaysWithinTheWindow(t *testing.T) {
+	const base, max = 100 * time.Millisecond, 2 * time.Second
+	for name, tc := range map[string]struct {
+		attempt int
+		ceil    time.Duration
+	}{
+		"first retry":    {attempt: 1, ceil: base},
+		"doubles":        {attempt: 3, ceil: 4 * base},
+		"capped at max":  {attempt: 10, ceil: max},
+		"shift overflow": {attempt: 200, ceil: max},
+		"no attempt yet": {attempt: 0, ceil: max},
+	} {
+		t.Run(name, func(t *testing.T) {
+			for range 1000 {
+				if d := FullJitter(tc.attempt, base, max); d < 0 || d >= tc.ceil {
+					t.Fatalf("FullJitter(%d) = %s, want in [0, %s)", tc.attempt, d, tc.ceil)
+				}
+			}
+		})
+	}
+}
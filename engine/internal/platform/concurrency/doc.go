@@ -0,0 +1,16 @@
+// Package concurrency provides an adaptive limit on in-flight requests, shared by
+// the HTTP and gRPC ingress.
+//
+// Design goals:
+//   - No static guess: the limit follows measured latency. Vegas estimates the
+//     queue building up in the server as limit × (1 − baseline/RTT) and grows the
+//     limit while that queue is small, shrinking it when it grows; AIMD adds
+//     slowly and cuts by a factor when RTT exceeds a tolerance over the baseline.
+//   - Moving baseline: the no-load RTT is the minimum seen, re-learned every
+//     BaselineResetEvery samples so it follows real shifts in service time.
+//   - Overload feedback: requests that end in a drop (503/504, Unavailable,
+//     DeadlineExceeded) cut the limit multiplicatively in both algorithms.
+//   - Only grow when used: the limit does not creep up while the server is idle.
+//   - Shadow mode: decisions are computed and logged but nothing is rejected,
+//     so a limit can be tuned on live traffic before it is enforced.
+package concurrency
@@ -0,0 +1,266 @@
+package concurrency
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"fintech-capstone/m/v2/internal/platform"
+)
+
+// Algorithm selects how the limit adapts.
+type Algorithm string
+
+const (
+	Vegas Algorithm = "vegas" // latency gradient: estimated queue against Alpha/Beta
+	AIMD  Algorithm = "aimd"  // additive increase, multiplicative decrease
+)
+
+// Outcome classifies a finished request for the limiter.
+type Outcome int
+
+const (
+	// Ignored releases the slot without a sample (client error, cancellation).
+	Ignored Outcome = iota
+	// Success samples the request's latency.
+	Success
+	// Dropped signals overload (timeout, shed downstream) and cuts the limit.
+	Dropped
+)
+
+// Config tunes the limiter.
+type Config struct {
+	Algorithm    Algorithm // "" => Vegas
+	InitialLimit int       // <= 0 => 20
+	MinLimit     int       // <= 0 => 1
+	MaxLimit     int       // <= 0 => 1024
+	// Alpha and Beta bound the estimated queue for Vegas: below Alpha the limit
+	// grows, above Beta it shrinks. <= 0 => 3 and 6.
+	Alpha, Beta int
+	// Tolerance is the RTT/baseline ratio above which AIMD backs off. <= 1 => 2.
+	Tolerance float64
+	// Backoff multiplies the limit on a drop (and on AIMD latency backoff).
+	// Outside (0, 1) => 0.9.
+	Backoff float64
+	// BaselineResetEvery re-learns the no-load RTT after this many samples. <= 0 => 1000.
+	BaselineResetEvery int
+	// NoiseFloor is the smallest RTT the algorithms compare; faster requests count as
+	// taking this long, so sub-millisecond jitter does not read as queueing. <= 0 => 1ms.
+	NoiseFloor time.Duration
+	// Shadow computes and logs decisions without rejecting anything.
+	Shadow bool
+}
+
+// Limiter is an adaptive in-flight limit. It is safe for concurrent use.
+type Limiter struct {
+	cfg    Config
+	logger platform.Logger
+
+	mu       sync.Mutex
+	limit    float64
+	inFlight int
+	rtt      time.Duration // smoothed (EWMA 1/8)
+	baseline time.Duration // no-load RTT estimate
+	window   time.Duration // lowest RTT since the last baseline reset
+	samples  int
+	adjusted time.Time // last Vegas adjustment
+	rejected int64     // would-be rejections too, in shadow mode
+
+	shadowSince   time.Time // last shadow log
+	shadowPending int64     // rejections not yet logged
+}
+
+// New creates a Limiter.
+func New(cfg Config, logger platform.Logger) *Limiter {
+	if cfg.Algorithm == "" {
+		cfg.Algorithm = Vegas
+	}
+	if cfg.MinLimit <= 0 {
+		cfg.MinLimit = 1
+	}
+	if cfg.MaxLimit <= 0 {
+		cfg.MaxLimit = 1024
+	}
+	if cfg.InitialLimit <= 0 {
+		cfg.InitialLimit = 20
+	}
+	if cfg.Alpha <= 0 {
+		cfg.Alpha = 3
+	}
+	if cfg.Beta <= 0 {
+		cfg.Beta = 6
+	}
+	if cfg.Tolerance <= 1 {
+		cfg.Tolerance = 2
+	}
+	if cfg.Backoff <= 0 || cfg.Backoff >= 1 {
+		cfg.Backoff = 0.9
+	}
+	if cfg.BaselineResetEvery <= 0 {
+		cfg.BaselineResetEvery = 1000
+	}
+	if cfg.NoiseFloor <= 0 {
+		cfg.NoiseFloor = time.Millisecond
+	}
+	l := &Limiter{cfg: cfg, logger: logger}
+	l.limit = l.clamp(float64(cfg.InitialLimit))
+	return l
+}
+
+// Token is the slot of one admitted request. Release it exactly once.
+type Token struct {
+	l     *Limiter
+	start time.Time
+}
+
+// Acquire admits a request while in-flight requests are under the limit. In shadow
+// mode it always admits, logging the rejections it would have made.
+func (l *Limiter) Acquire() (Token, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inFlight >= int(l.limit) {
+		l.rejected++
+		if !l.cfg.Shadow {
+			return Token{}, false
+		}
+		l.logShadow()
+	}
+	l.inFlight++
+	return Token{l: l, start: time.Now()}, true
+}
+
+// Release frees the slot and feeds the outcome back into the limit.
+func (t Token) Release(o Outcome) {
+	if t.l == nil {
+		return
+	}
+	t.l.release(time.Since(t.start), o)
+}
+
+func (l *Limiter) release(rtt time.Duration, o Outcome) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Utilised: the limit was actually being used when this request ran.
+	utilised := l.inFlight*2 >= int(l.limit)
+	l.inFlight--
+
+	before := int(l.limit)
+	switch o {
+	case Dropped:
+		l.limit = l.clamp(l.limit * l.cfg.Backoff)
+	case Success:
+		l.sample(rtt)
+		l.adapt(rtt, utilised)
+	}
+	if after := int(l.limit); after != before && l.logger != nil {
+		l.logger.Debug("adaptive concurrency limit changed",
+			platform.Field{Key: "from", Value: before},
+			platform.Field{Key: "to", Value: after},
+			platform.Field{Key: "rtt_ms", Value: msec(l.rtt)},
+			platform.Field{Key: "baseline_ms", Value: msec(l.baseline)},
+			platform.Field{Key: "shadow", Value: l.cfg.Shadow},
+		)
+	}
+}
+
+// sample updates the smoothed RTT and the baseline. Caller must hold l.mu.
+func (l *Limiter) sample(rtt time.Duration) {
+	if rtt <= 0 {
+		rtt = time.Microsecond
+	}
+	l.samples++
+	if l.window == 0 || rtt < l.window {
+		l.window = rtt
+	}
+	if l.baseline == 0 || rtt < l.baseline {
+		l.baseline = rtt
+	}
+	// Re-learn from the window's minimum, so the baseline can also move up.
+	if l.samples%l.cfg.BaselineResetEvery == 0 {
+		l.baseline, l.window = l.window, 0
+	}
+	if l.rtt == 0 {
+		l.rtt = rtt
+	} else {
+		l.rtt += (rtt - l.rtt) / 8
+	}
+}
+
+// adapt moves the limit for one successful sample. Vegas reads the smoothed RTT
+// and steps at most once per RTT, AIMD reacts to the sample itself. Caller must hold l.mu.
+func (l *Limiter) adapt(rtt time.Duration, utilised bool) {
+	baseline := max(l.baseline, l.cfg.NoiseFloor)
+	switch l.cfg.Algorithm {
+	case AIMD:
+		if float64(max(rtt, l.cfg.NoiseFloor)) > l.cfg.Tolerance*float64(baseline) {
+			l.limit = l.clamp(l.limit * l.cfg.Backoff)
+		} else if utilised {
+			l.limit = l.clamp(l.limit + 1/l.limit) // about +1 per limit's worth of requests
+		}
+	default:
+		// One step per round trip: faster steps outrun the RTT feedback and oscillate.
+		now := time.Now()
+		if now.Sub(l.adjusted) < l.rtt {
+			return
+		}
+		l.adjusted = now
+		queue := l.limit * (1 - float64(baseline)/float64(max(l.rtt, l.cfg.NoiseFloor)))
+		switch {
+		case queue > float64(l.cfg.Beta):
+			l.limit = l.clamp(l.limit - 1)
+		case queue < float64(l.cfg.Alpha) && utilised:
+			l.limit = l.clamp(l.limit + 1)
+		}
+	}
+}
+
+// logShadow reports would-be rejections at most once a second. Caller must hold l.mu.
+func (l *Limiter) logShadow() {
+	l.shadowPending++
+	if l.logger == nil || time.Since(l.shadowSince) < time.Second {
+		return
+	}
+	l.logger.Info("adaptive concurrency (shadow) would reject",
+		platform.Field{Key: "count", Value: l.shadowPending},
+		platform.Field{Key: "limit", Value: int(l.limit)},
+		platform.Field{Key: "in_flight", Value: l.inFlight},
+		platform.Field{Key: "rtt_ms", Value: msec(l.rtt)},
+		platform.Field{Key: "baseline_ms", Value: msec(l.baseline)},
+	)
+	l.shadowSince = time.Now()
+	l.shadowPending = 0
+}
+
+func (l *Limiter) clamp(v float64) float64 {
+	return math.Max(float64(l.cfg.MinLimit), math.Min(float64(l.cfg.MaxLimit), v))
+}
+
+// Stats is a point-in-time view of the limiter for observability.
+type Stats struct {
+	Algorithm   Algorithm
+	Limit       int
+	InFlight    int
+	RTT         time.Duration // smoothed
+	BaselineRTT time.Duration
+	Rejected    int64 // in shadow mode: requests that would have been rejected
+	Shadow      bool
+}
+
+// Stats returns a snapshot for observability.
+func (l *Limiter) Stats() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return Stats{
+		Algorithm:   l.cfg.Algorithm,
+		Limit:       int(l.limit),
+		InFlight:    l.inFlight,
+		RTT:         l.rtt,
+		BaselineRTT: l.baseline,
+		Rejected:    l.rejected,
+		Shadow:      l.cfg.Shadow,
+	}
+}
+
+func msec(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
@@ -0,0 +1,116 @@
+package concurrency
+
+import (
+	"testing"
+	"time"
+)
+
+// feed releases n requests that each took rtt and ended with o, with inFlight
+// other requests running alongside. Vegas may step on every sample.
+func feed(l *Limiter, n, inFlight int, rtt time.Duration, o Outcome) {
+	for range n {
+		l.mu.Lock()
+		l.inFlight = inFlight + 1
+		l.adjusted = time.Time{}
+		l.mu.Unlock()
+		l.release(rtt, o)
+	}
+}
+
+func TestVegasGrowsOnlyWhileTheLimitIsUsed(t *testing.T) {
+	for name, tc := range map[string]struct {
+		inFlight int
+		want     int
+	}{
+		"used": {inFlight: 10, want: 15},
+		"idle": {inFlight: 0, want: 10},
+	} {
+		t.Run(name, func(t *testing.T) {
+			l := New(Config{Algorithm: Vegas, InitialLimit: 10}, nil)
+			feed(l, 5, tc.inFlight, 10*time.Millisecond, Success)
+			if got := l.Stats().Limit; got != tc.want {
+				t.Fatalf("limit = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestVegasShrinksAsTheQueueBuilds(t *testing.T) {
+	l := New(Config{Algorithm: Vegas, InitialLimit: 20}, nil)
+	feed(l, 1, 20, 10*time.Millisecond, Success) // baseline
+	start := l.Stats().Limit
+	feed(l, 10, 20, 100*time.Millisecond, Success)
+	if got := l.Stats().Limit; got >= start {
+		t.Fatalf("limit = %d after RTT rose tenfold, want below %d", got, start)
+	}
+}
+
+func TestAIMD(t *testing.T) {
+	for name, tc := range map[string]struct {
+		rtt  time.Duration
+		o    Outcome
+		n    int
+		want int
+	}{
+		"adds about one per limit's worth": {rtt: 10 * time.Millisecond, o: Success, n: 20, want: 11},
+		"backs off past the tolerance":     {rtt: 30 * time.Millisecond, o: Success, n: 1, want: 9},
+		"backs off on a drop":              {rtt: 10 * time.Millisecond, o: Dropped, n: 1, want: 9},
+		"ignores ignored":                  {rtt: time.Second, o: Ignored, n: 5, want: 10},
+	} {
+		t.Run(name, func(t *testing.T) {
+			l := New(Config{Algorithm: AIMD, InitialLimit: 10}, nil)
+			l.mu.Lock()
+			l.baseline = 10 * time.Millisecond
+			l.mu.Unlock()
+			feed(l, tc.n, 10, tc.rtt, tc.o)
+			if got := l.Stats().Limit; got != tc.want {
+				t.Fatalf("limit = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLimitStaysWithinBounds(t *testing.T) {
+	for _, algo := range []Algorithm{Vegas, AIMD} {
+		t.Run(string(algo), func(t *testing.T) {
+			l := New(Config{Algorithm: algo, InitialLimit: 100, MinLimit: 4, MaxLimit: 12}, nil)
+			if got := l.Stats().Limit; got != 12 {
+				t.Fatalf("initial limit = %d, want it clamped to 12", got)
+			}
+			feed(l, 200, 12, time.Millisecond, Success)
+			if got := l.Stats().Limit; got != 12 {
+				t.Fatalf("limit = %d after growing, want 12", got)
+			}
+			feed(l, 50, 12, time.Millisecond, Dropped)
+			if got := l.Stats().Limit; got != 4 {
+				t.Fatalf("limit = %d after drops, want 4", got)
+			}
+		})
+	}
+}
+
+func TestShadowModeNeverRejects(t *testing.T) {
+	for name, tc := range map[string]struct {
+		shadow   bool
+		admitted int
+	}{
+		"enforced": {shadow: false, admitted: 2},
+		"shadow":   {shadow: true, admitted: 5},
+	} {
+		t.Run(name, func(t *testing.T) {
+			l := New(Config{InitialLimit: 2, MaxLimit: 2, Shadow: tc.shadow}, nil)
+			admitted := 0
+			for range 5 {
+				if _, ok := l.Acquire(); ok {
+					admitted++
+				}
+			}
+			if admitted != tc.admitted {
+				t.Fatalf("admitted %d of 5, want %d", admitted, tc.admitted)
+			}
+			if got := l.Stats().Rejected; got != 3 {
+				t.Fatalf("rejected = %d, want 3 (counted in shadow mode too)", got)
+			}
+		})
+	}
+}
@@ -1,3 +1,6 @@
-// Package platform provides shared infrastructure: logging, HTTP middleware, and
-// standardized application errors used across transports.
+// Package platform provides shared infrastructure: logging, HTTP middleware, an
+// adaptive concurrency limit, atomic snapshot-file writes (file_kit), full-jitter
+// retry delays (backoff), and standardized application errors used across
+// transports, with their HTTP (http_kit/writer) and gRPC (grpc_kit/errstatus)
+// forms.
 package platform
@@ -0,0 +1,48 @@
+// Package file_kit holds file helpers shared by the adapters that keep their state
+// in snapshot files (dead letters, the job journal, webhooks).
+package file_kit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WriteAtomic replaces the file at path with data. It writes a temporary file
+// next to it, syncs it and renames it over path, then syncs the directory, so a
+// crash leaves either the old content or the new, never a torn file. The
+// temporary file is removed if any step fails.
+func WriteAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("write: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("sync: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("replace: %w", err)
+	}
+	// The rename is durable only once the directory entry is.
+	if d, err := os.Open(filepath.Dir(path)); err == nil {
+		err = d.Sync()
+		d.Close()
+		if err != nil {
+			return fmt.Errorf("sync dir: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package file_kit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteAtomicReplacesTheFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	for _, body := range []string{`{"v":1}`, `{"v":2}`} {
+		if err := WriteAtomic(path, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if b, err := os.ReadFile(path); err != nil || string(b) != body {
+			t.Fatalf("read %q, %v; want %q", b, err, body)
+		}
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("temporary file left behind: %v", err)
+	}
+}
+
+func TestWriteAtomicKeepsTheOldFileOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+	if err := WriteAtomic(path, []byte("old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// A directory in the way of the rename makes the replace step fail.
+	if err := os.Mkdir(path+".d", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteAtomic(path+".d", []byte("new"), 0o600); err == nil {
+		t.Fatal("replacing a directory succeeded")
+	}
+	if _, err := os.Stat(path + ".d.tmp"); !os.IsNotExist(err) {
+		t.Fatalf("temporary file left behind: %v", err)
+	}
+	if b, _ := os.ReadFile(path); string(b) != "old" {
+		t.Fatalf("file = %q, want the old content", b)
+	}
+}
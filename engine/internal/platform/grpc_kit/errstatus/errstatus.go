@@ -0,0 +1,75 @@
+// Package errstatus maps application errors (apperr) to gRPC statuses, the gRPC
+// counterpart of http_kit/writer's error responses.
+package errstatus
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"fintech-capstone/m/v2/internal/platform/apperr"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// retryInfoType is the type URL of google.rpc.RetryInfo, the standard detail
+// gRPC clients read their backoff hint from.
+const retryInfoType = "type.googleapis.com/google.rpc.RetryInfo"
+
+// FromError converts an application error to a gRPC status error. A RetryAfter
+// hint travels as a google.rpc.RetryInfo detail.
+func FromError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	e := apperr.As(err)
+	var code codes.Code
+
+	switch e.Code {
+	case apperr.CodeInvalid:
+		code = codes.InvalidArgument
+	case apperr.CodeRateLimited:
+		code = codes.ResourceExhausted
+	case apperr.CodeTimeout:
+		code = codes.DeadlineExceeded
+	case apperr.CodeNotFound:
+		code = codes.NotFound
+	case apperr.CodeConflict:
+		code = codes.AlreadyExists // clearer than Aborted for idempotency
+	case apperr.CodePayloadTooLarge:
+		code = codes.ResourceExhausted
+	case apperr.CodeOverloaded:
+		code = codes.Unavailable
+	case apperr.CodeInProgress:
+		code = codes.Aborted // retry the whole request, per gRPC's concurrency-conflict guidance
+	default:
+		code = codes.Internal
+	}
+
+	// Preserve root cause if present
+	msg := e.Msg
+	if e.Err != nil && !errors.Is(err, e.Err) {
+		msg = fmt.Sprintf("%s: %v", e.Msg, e.Err)
+	}
+	if e.RetryAfter <= 0 {
+		return status.Error(code, msg)
+	}
+	p := status.New(code, msg).Proto()
+	p.Details = append(p.Details, retryInfo(e.RetryAfter))
+	return status.FromProto(p).Err()
+}
+
+// retryInfo encodes a google.rpc.RetryInfo{retry_delay: d} detail by hand, as the
+// errdetails package is not vendored: retry_delay is field 1, a Duration message.
+func retryInfo(d time.Duration) *anypb.Any {
+	delay, _ := proto.Marshal(durationpb.New(d)) // a Duration always marshals
+	b := protowire.AppendTag(nil, 1, protowire.BytesType)
+	b = protowire.AppendBytes(b, delay)
+	return &anypb.Any{TypeUrl: retryInfoType, Value: b}
+}
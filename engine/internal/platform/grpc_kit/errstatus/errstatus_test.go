@@ -0,0 +1,103 @@
+package errstatus
+
+import (
+	"testing"
+	"time"
+
+	"fintech-capstone/m/v2/internal/platform/apperr"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// decodeRetryInfo parses a google.rpc.RetryInfo message by its schema
+// (retry_delay = 1, a google.protobuf.Duration), as errdetails is not vendored.
+func decodeRetryInfo(t *testing.T, b []byte) time.Duration {
+	t.Helper()
+	var delay *durationpb.Duration
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatalf("RetryInfo: %v", protowire.ParseError(n))
+		}
+		b = b[n:]
+		if num == 1 && typ == protowire.BytesType {
+			v, m := protowire.ConsumeBytes(b)
+			if m < 0 {
+				t.Fatalf("retry_delay: %v", protowire.ParseError(m))
+			}
+			delay = new(durationpb.Duration)
+			if err := proto.Unmarshal(v, delay); err != nil {
+				t.Fatalf("retry_delay: %v", err)
+			}
+			n = m
+		} else {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		b = b[n:]
+	}
+	if delay == nil {
+		t.Fatal("RetryInfo has no retry_delay")
+	}
+	return delay.AsDuration()
+}
+
+func TestRetryAfterTravelsAsRetryInfo(t *testing.T) {
+	err := FromError(apperr.Wrap(apperr.CodeRateLimited, "rate limit exceeded", nil).WithRetryAfter(1500 * time.Millisecond))
+
+	// Round-trip the status through its wire form, as a client receives it.
+	wire, merr := proto.Marshal(status.Convert(err).Proto())
+	if merr != nil {
+		t.Fatal(merr)
+	}
+	var got spb.Status
+	if err := proto.Unmarshal(wire, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if codes.Code(got.GetCode()) != codes.ResourceExhausted {
+		t.Fatalf("code = %s, want ResourceExhausted", codes.Code(got.GetCode()))
+	}
+	if len(got.GetDetails()) != 1 {
+		t.Fatalf("details = %v, want one RetryInfo", got.GetDetails())
+	}
+	detail := got.GetDetails()[0]
+	if detail.GetTypeUrl() != "type.googleapis.com/google.rpc.RetryInfo" {
+		t.Fatalf("type URL = %q", detail.GetTypeUrl())
+	}
+	if d := decodeRetryInfo(t, detail.GetValue()); d != 1500*time.Millisecond {
+		t.Fatalf("retry_delay = %s, want 1.5s", d)
+	}
+}
+
+func TestNoRetryInfoWithoutHint(t *testing.T) {
+	st := status.Convert(FromError(apperr.Invalid("bad amount")))
+	if st.Code() != codes.InvalidArgument || len(st.Proto().GetDetails()) != 0 {
+		t.Fatalf("got %s with %d details, want InvalidArgument without details", st.Code(), len(st.Proto().GetDetails()))
+	}
+}
+
+func TestCodes(t *testing.T) {
+	for code, want := range map[apperr.Code]codes.Code{
+		apperr.CodeInvalid:         codes.InvalidArgument,
+		apperr.CodeRateLimited:     codes.ResourceExhausted,
+		apperr.CodeTimeout:         codes.DeadlineExceeded,
+		apperr.CodeNotFound:        codes.NotFound,
+		apperr.CodeConflict:        codes.AlreadyExists,
+		apperr.CodePayloadTooLarge: codes.ResourceExhausted,
+		apperr.CodeOverloaded:      codes.Unavailable,
+		apperr.CodeInProgress:      codes.Aborted,
+		apperr.CodeInternal:        codes.Internal,
+	} {
+		if got := status.Code(FromError(&apperr.Error{Code: code, Msg: "x"})); got != want {
+			t.Errorf("apperr code %d => %s, want %s", code, got, want)
+		}
+	}
+	if FromError(nil) != nil {
+		t.Error("nil error mapped to a status")
+	}
+}
@@ -2,8 +2,11 @@ package limiter
 
 import (
 	"context"
+	"math"
 	"sync"
 	"time"
+
+	"fintech-capstone/m/v2/internal/platform/http_kit/middleware"
 )
 
 // LightLimiter provides a tiny local rate limiter for basic DoS protection.
@@ -29,8 +32,9 @@ func NewLightLimiter(rate float64, burst int) *LightLimiter {
 	}
 }
 
-// Allow checks if a request with the given key is allowed.
-func (l *LightLimiter) Allow(_ context.Context, key string) bool {
+// Allow checks if a request with the given key is allowed. It satisfies
+// middleware.AllowFunc.
+func (l *LightLimiter) Allow(_ context.Context, key string) middleware.RateDecision {
 	now := time.Now()
 
 	l.mu.Lock()
@@ -45,11 +49,23 @@ func (l *LightLimiter) Allow(_ context.Context, key string) bool {
 		b.tokens = l.burst
 	}
 	b.last = now
-	allowed := b.tokens >= 1
-	if allowed {
+	d := middleware.RateDecision{Allowed: b.tokens >= 1, Limit: int(l.burst)}
+	if d.Allowed {
 		b.tokens -= 1
+	} else {
+		d.RetryAfter = l.wait(1 - b.tokens)
 	}
+	d.Remaining = int(b.tokens)
+	d.Reset = l.wait(l.burst - b.tokens)
 	l.mu.Unlock()
 
-	return allowed
+	return d
+}
+
+// wait is how long the bucket takes to refill n tokens.
+func (l *LightLimiter) wait(n float64) time.Duration {
+	if n <= 0 || l.rate <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(n / l.rate * float64(time.Second)))
 }
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+
+	"fintech-capstone/m/v2/internal/platform/concurrency"
+	"fintech-capstone/m/v2/internal/platform/http_kit/writer"
+)
+
+// AdaptiveConcurrency admits requests while the in-flight count is under l's adaptive
+// limit and answers 503 otherwise. 503 and 504 responses count as drops; 4xx and
+// cancelled requests release their slot without a latency sample. skip exempts
+// long-lived requests such as event streams (nil => none).
+func AdaptiveConcurrency(l *concurrency.Limiter, skip func(*http.Request) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if skip != nil && skip(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			tok, ok := l.Acquire()
+			if !ok {
+				_ = r.Body.Close()
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, "server busy", http.StatusServiceUnavailable)
+				return
+			}
+
+			outcome := concurrency.Ignored // if next panics
+			defer func() { tok.Release(outcome) }()
+
+			sw := writer.Wrap(w)
+			next.ServeHTTP(sw, r)
+
+			code, _, _ := writer.Status(sw)
+			switch {
+			case code == http.StatusServiceUnavailable || code == http.StatusGatewayTimeout:
+				outcome = concurrency.Dropped
+			case r.Context().Err() != nil || (code >= 400 && code < 500):
+				outcome = concurrency.Ignored
+			default:
+				outcome = concurrency.Success
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+// PreferAsync sends POST requests for path that carry `Prefer: respond-async`
+// (RFC 7240) to async instead of next, and marks the preference as applied.
+func PreferAsync(path string, async http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost && r.URL.Path == path && prefersAsync(r) {
+				w.Header().Set("Preference-Applied", "respond-async")
+				async.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// prefersAsync reports whether any Prefer header asks for respond-async.
+func prefersAsync(r *http.Request) bool {
+	for _, v := range r.Header.Values("Prefer") {
+		for _, pref := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(pref), "respond-async") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -6,10 +6,21 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"fintech-capstone/m/v2/internal/platform/http_kit/writer"
 )
 
+// RateDecision is a rate limiter's verdict on one request.
+type RateDecision struct {
+	Allowed    bool
+	Limit      int           // burst size; 0 => unlimited, no RateLimit-* headers
+	Remaining  int           // whole tokens left after this request
+	Reset      time.Duration // until the bucket is full again
+	RetryAfter time.Duration // until the next token, when refused
+}
+
 // AllowFunc is a minimal adapter to allow various rate limiting decisions.
-type AllowFunc func(ctx context.Context, key string) bool
+type AllowFunc func(ctx context.Context, key string) RateDecision
 
 // ClientKeyFromRequest picks API key, then X-Forwarded-For, then RemoteAddr.
 func ClientKeyFromRequest(r *http.Request) string {
@@ -40,7 +51,8 @@ func clientIP(r *http.Request) string {
 	return host
 }
 
-// RateLimitHTTP rejects early (429) without reading the body.
+// RateLimitHTTP rejects early (429) without reading the body. Every response
+// carries the RateLimit-* headers, and a 429 says in Retry-After when to retry.
 func RateLimitHTTP(allow AllowFunc) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -49,10 +61,12 @@ func RateLimitHTTP(allow AllowFunc) func(http.Handler) http.Handler {
 			defer cancel()
 
 			key := ClientKeyFromRequest(r)
-			if !allow(ctx, key) {
+			d := allow(ctx, key)
+			writer.RateLimit(w, d.Limit, d.Remaining, d.Reset)
+			if !d.Allowed {
 				// close body to free the conn; as it was not read.
 				_ = r.Body.Close()
-				w.Header().Set("Retry-After", "1")
+				writer.RetryAfter(w, max(d.RetryAfter, time.Second))
 				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
 				return
 			}
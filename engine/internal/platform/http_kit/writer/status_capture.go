@@ -25,6 +25,11 @@ func (w *statusCapture) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Unwrap exposes the underlying writer to http.ResponseController (flushing, deadlines).
+func (w *statusCapture) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 // Wrap wraps an http.ResponseWriter to capture status code and bytes written.
 func Wrap(w http.ResponseWriter) *statusCapture {
 	return &statusCapture{ResponseWriter: w}
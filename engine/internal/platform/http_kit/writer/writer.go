@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 // JSON writes a JSON response with the given status code.
@@ -31,3 +33,26 @@ func JSON(w http.ResponseWriter, status int, v any) {
 func Error(w http.ResponseWriter, status int, msg string) {
 	JSON(w, status, map[string]string{"error": msg})
 }
+
+// RateLimit sets the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset
+// headers (IETF httpapi ratelimit-headers draft); reset is rounded up to whole
+// seconds. limit <= 0 (unlimited) leaves them unset.
+func RateLimit(w http.ResponseWriter, limit, remaining int, reset time.Duration) {
+	if limit <= 0 {
+		return
+	}
+	h := w.Header()
+	h.Set("RateLimit-Limit", strconv.Itoa(limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(max(0, remaining)))
+	h.Set("RateLimit-Reset", strconv.FormatInt(int64((reset+time.Second-1)/time.Second), 10))
+}
+
+// RetryAfter sets the Retry-After header to d in whole seconds, rounded up so a
+// client never retries before the hint. d <= 0 leaves the header unset.
+func RetryAfter(w http.ResponseWriter, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	secs := int64((d + time.Second - 1) / time.Second)
+	w.Header().Set("Retry-After", strconv.FormatInt(secs, 10))
+}
@@ -0,0 +1,118 @@
+package retry_budget
+
+import (
+	"sync"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/contracts"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform/backoff"
+)
+
+// Compile-time check that *Budget implements outbound.Retrier.
+var _ outbound.Retrier = (*Budget)(nil)
+
+// Config tunes a Budget.
+type Config struct {
+	MaxRetries int // retries per request on top of the first attempt. <= 0 => 2
+	// Ratio is the share of a token each first attempt deposits. <= 0 => 0.1.
+	Ratio float64
+	// MinPerSecond is refilled regardless of traffic. <= 0 => 1.
+	MinPerSecond float64
+	// Burst caps the balance, i.e. how many retries an outage can draw at once. <= 0 => 20.
+	Burst float64
+	// BaseBackoff and MaxBackoff shape the full-jitter backoff. <= 0 => 20ms / 500ms.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// Budget is a token-bucket retry budget. It is safe for concurrent use.
+type Budget struct {
+	cfg Config
+
+	mu      sync.Mutex
+	tokens  float64
+	refill  time.Time // last MinPerSecond refill
+	retries int64
+	denied  int64
+}
+
+// New creates a Budget that starts full.
+func New(cfg Config) *Budget {
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 2
+	}
+	if cfg.Ratio <= 0 {
+		cfg.Ratio = 0.1
+	}
+	if cfg.MinPerSecond <= 0 {
+		cfg.MinPerSecond = 1
+	}
+	if cfg.Burst <= 0 {
+		cfg.Burst = 20
+	}
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = 20 * time.Millisecond
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 500 * time.Millisecond
+	}
+	return &Budget{cfg: cfg, tokens: cfg.Burst, refill: time.Now()}
+}
+
+// Deposit implements outbound.Retrier.
+func (b *Budget) Deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.cfg.Burst, b.tokens+b.cfg.Ratio)
+}
+
+// Retry implements outbound.Retrier.
+func (b *Budget) Retry(attempt int, deadline time.Time) (time.Duration, bool) {
+	if attempt < 1 || attempt > b.cfg.MaxRetries {
+		return 0, false
+	}
+	now := time.Now()
+	wait := backoff.FullJitter(attempt, b.cfg.BaseBackoff, b.cfg.MaxBackoff)
+	if !deadline.IsZero() && !now.Add(wait).Before(deadline) {
+		return 0, false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refillLocked(time.Now())
+	if b.tokens < 1 {
+		b.denied++
+		return 0, false
+	}
+	b.tokens--
+	b.retries++
+	return wait, true
+}
+
+// refillLocked adds the time-based MinPerSecond share. Caller must hold b.mu.
+func (b *Budget) refillLocked(now time.Time) {
+	b.tokens = min(b.cfg.Burst, b.tokens+now.Sub(b.refill).Seconds()*b.cfg.MinPerSecond)
+	b.refill = now
+}
+
+// Stats is a point-in-time view of the budget for observability.
+type Stats struct {
+	Tokens  float64
+	Retries int64 // retries granted
+	Denied  int64 // retries refused because the budget was empty
+}
+
+// Stats returns a snapshot for observability.
+func (b *Budget) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refillLocked(time.Now())
+	return Stats{Tokens: b.tokens, Retries: b.retries, Denied: b.denied}
+}
+
+// Snapshot returns the budget's stats as a wire contract.
+func (b *Budget) Snapshot() contracts.RetrySnapshot {
+	st := b.Stats()
+	return contracts.RetrySnapshot{Tokens: st.Tokens, Retries: st.Retries, Denied: st.Denied}
+}
@@ -0,0 +1,15 @@
+// Package retry_budget provides a token-bucket retry budget that implements
+// outbound.Retrier for the retry policy stage.
+//
+// Design goals:
+//   - Retries are earned: every first attempt deposits Ratio of a token and every
+//     retry spends a whole one, so retries stay a bounded fraction of traffic
+//     (10% by default) and cannot multiply load during an outage.
+//   - A small MinPerSecond refill keeps low-traffic services able to retry.
+//   - Full-jitter exponential backoff, like webhook delivery, so retrying callers
+//     spread out instead of hammering a recovering dependency in lockstep.
+//   - Non-blocking: Retry only computes the wait; the caller sleeps and respects
+//     its own deadline.
+//   - Cheap observability: retries granted, denied by the budget, and the
+//     current balance.
+package retry_budget
@@ -0,0 +1,8 @@
+// Package transfer_status provides an in-memory outbound.TransferStatuses for
+// asynchronously submitted transfers.
+//
+// Design goals:
+//   - Sharded map keyed by transaction ID to keep lock contention low.
+//   - Finished transfers are kept for a TTL so clients can poll them, then
+//     evicted by a background janitor; pending transfers are never evicted.
+package transfer_status
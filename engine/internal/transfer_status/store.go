@@ -0,0 +1,118 @@
+package transfer_status
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+
+	"github.com/google/uuid"
+)
+
+// Compile-time check that *Store implements outbound.TransferStatuses.
+var _ outbound.TransferStatuses = (*Store)(nil)
+
+// Config controls retention of finished transfers.
+type Config struct {
+	// TTL keeps finished transfers pollable for this long. <= 0 => 1h.
+	TTL             time.Duration
+	NumShards       int           // <= 0 => 64
+	CleanupInterval time.Duration // <= 0 => 1m
+}
+
+type shard struct {
+	mu   sync.Mutex
+	data map[uuid.UUID]outbound.TransferStatus
+}
+
+// Store is an in-memory outbound.TransferStatuses. It is safe for concurrent use.
+type Store struct {
+	shards []shard
+
+	cleanupTicker   *time.Ticker
+	stopCleanupChan chan struct{}
+	stopOnce        sync.Once
+}
+
+// New creates a Store. Provide a context that is cancelled on server shutdown to
+// stop background cleanup.
+func New(ctx context.Context, cfg Config) *Store {
+	if cfg.TTL <= 0 {
+		cfg.TTL = time.Hour
+	}
+	if cfg.NumShards <= 0 {
+		cfg.NumShards = 64
+	}
+	if cfg.CleanupInterval <= 0 {
+		cfg.CleanupInterval = time.Minute
+	}
+
+	s := &Store{
+		shards:          make([]shard, cfg.NumShards),
+		stopCleanupChan: make(chan struct{}),
+	}
+	for i := range s.shards {
+		s.shards[i].data = make(map[uuid.UUID]outbound.TransferStatus, 256)
+	}
+
+	// Background janitor: evict finished transfers past their TTL.
+	s.cleanupTicker = time.NewTicker(cfg.CleanupInterval)
+	go func() {
+		defer s.cleanupTicker.Stop()
+		for {
+			select {
+			case <-s.cleanupTicker.C:
+				s.cleanup(cfg.TTL)
+			case <-ctx.Done():
+				return
+			case <-s.stopCleanupChan:
+				return
+			}
+		}
+	}()
+	return s
+}
+
+// Stop stops the background cleanup goroutine (optional; otherwise it exits when ctx is cancelled).
+func (s *Store) Stop() { s.stopOnce.Do(func() { close(s.stopCleanupChan) }) }
+
+// Put implements outbound.TransferStatuses.
+func (s *Store) Put(st outbound.TransferStatus) {
+	sh := s.getShard(st.TransactionID)
+	sh.mu.Lock()
+	sh.data[st.TransactionID] = st
+	sh.mu.Unlock()
+}
+
+// Get implements outbound.TransferStatuses.
+func (s *Store) Get(id uuid.UUID) (outbound.TransferStatus, bool) {
+	sh := s.getShard(id)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	st, ok := sh.data[id]
+	return st, ok
+}
+
+func (s *Store) getShard(id uuid.UUID) *shard {
+	// UUIDs are already uniformly distributed; no need to hash them again.
+	return &s.shards[binary.BigEndian.Uint32(id[12:])%uint32(len(s.shards))]
+}
+
+// cleanup evicts finished transfers older than ttl across shards.
+func (s *Store) cleanup(ttl time.Duration) (evicted int) {
+	now := time.Now()
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.mu.Lock()
+		for id, st := range sh.data {
+			if st.State != outbound.TransferPending && now.Sub(st.CompletedAt) >= ttl {
+				delete(sh.data, id)
+				evicted++
+			}
+		}
+		sh.mu.Unlock()
+	}
+	return
+}
@@ -0,0 +1,53 @@
+package webhook
+
+import "time"
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// breaker is a consecutive-failure circuit breaker for one endpoint.
+// It is guarded by Service.mu.
+type breaker struct {
+	state     breakerState
+	failures  int
+	openUntil time.Time
+	probing   bool // half-open: one probe in flight
+}
+
+// allow reports whether an attempt may go out now, and if not, when to retry.
+func (b *breaker) allow(now time.Time) (bool, time.Time) {
+	switch b.state {
+	case breakerOpen:
+		if now.Before(b.openUntil) {
+			return false, b.openUntil
+		}
+		b.state = breakerHalfOpen
+		fallthrough
+	case breakerHalfOpen:
+		if b.probing {
+			return false, now.Add(time.Second)
+		}
+		b.probing = true
+		return true, time.Time{}
+	default:
+		return true, time.Time{}
+	}
+}
+
+func (b *breaker) success() {
+	*b = breaker{}
+}
+
+func (b *breaker) failure(now time.Time, threshold int, cooldown time.Duration) {
+	b.failures++
+	b.probing = false
+	if b.state == breakerHalfOpen || b.failures >= threshold {
+		b.state = breakerOpen
+		b.openUntil = now.Add(cooldown)
+	}
+}
@@ -0,0 +1,65 @@
+package webhook
+
+import "time"
+
+// Config controls delivery, retries and endpoint health.
+type Config struct {
+	// Path of the durable snapshot file.
+	Path string
+	// Concurrency caps deliveries in flight across endpoints. <= 0 => 8.
+	Concurrency int
+	// PollInterval is how often due deliveries are picked up. <= 0 => 1s.
+	PollInterval time.Duration
+	// RequestTimeout bounds one delivery attempt. <= 0 => 5s.
+	RequestTimeout time.Duration
+	// MaxAttempts before a delivery is given up. <= 0 => 12.
+	MaxAttempts int
+	// BaseBackoff and MaxBackoff shape the full-jitter backoff. <= 0 => 1s / 1h.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// BreakerThreshold consecutive failures open an endpoint's breaker. <= 0 => 5.
+	BreakerThreshold int
+	// BreakerCooldown keeps the breaker open before a probe. <= 0 => 30s.
+	BreakerCooldown time.Duration
+	// DisableAfter consecutive failures disable the subscription. <= 0 => 50.
+	DisableAfter int
+	// LogSize caps the delivery log. <= 0 => 1000.
+	LogSize int
+	// AllowPrivateTargets lets subscriptions point at loopback, link-local and
+	// private addresses. For local development only: it opens the gateway's own
+	// network to client-chosen requests.
+	AllowPrivateTargets bool
+}
+
+func (c *Config) defaults() {
+	if c.Concurrency <= 0 {
+		c.Concurrency = 8
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = time.Second
+	}
+	if c.RequestTimeout <= 0 {
+		c.RequestTimeout = 5 * time.Second
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 12
+	}
+	if c.BaseBackoff <= 0 {
+		c.BaseBackoff = time.Second
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = time.Hour
+	}
+	if c.BreakerThreshold <= 0 {
+		c.BreakerThreshold = 5
+	}
+	if c.BreakerCooldown <= 0 {
+		c.BreakerCooldown = 30 * time.Second
+	}
+	if c.DisableAfter <= 0 {
+		c.DisableAfter = 50
+	}
+	if c.LogSize <= 0 {
+		c.LogSize = 1000
+	}
+}
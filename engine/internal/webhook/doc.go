@@ -0,0 +1,16 @@
+// Package webhook delivers signed transfer lifecycle events to client endpoints.
+//
+// Design goals:
+//   - Durable: subscriptions, pending deliveries and the delivery log live in a
+//     snapshot file that is replaced atomically, so deliveries survive restarts.
+//     A single background writer replaces it, so publishing never waits on disk.
+//   - Safe targets: endpoints must be public http(s) addresses, checked when
+//     subscribing and again on every connection.
+//   - Signed: each request carries Webhook-Id, Webhook-Timestamp and a
+//     Webhook-Signature (HMAC-SHA256 over "<timestamp>.<body>" with the
+//     subscription secret); receivers check it with Verify.
+//   - Polite retries: exponential backoff with full jitter, capped attempts.
+//   - Isolation: a per-endpoint circuit breaker stops hammering a failing
+//     endpoint, and endpoints that keep failing are disabled until re-enabled.
+//   - Observable: every attempt is recorded in a bounded, queryable log.
+package webhook
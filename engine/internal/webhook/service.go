@@ -0,0 +1,579 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+	"fintech-capstone/m/v2/internal/platform/backoff"
+	"fintech-capstone/m/v2/internal/platform/file_kit"
+
+	"github.com/google/uuid"
+)
+
+// Compile-time checks that *Service implements the event ports.
+var (
+	_ outbound.EventPublisher = (*Service)(nil)
+	_ outbound.Webhooks       = (*Service)(nil)
+)
+
+// Delivery outcomes recorded in the log.
+const (
+	OutcomeDelivered = "delivered"
+	OutcomeRetrying  = "retrying"
+	OutcomeFailed    = "failed"
+)
+
+// delivery is one event waiting to reach one subscription.
+type delivery struct {
+	ID             string                 `json:"id"`
+	SubscriptionID string                 `json:"subscription_id"`
+	Event          outbound.TransferEvent `json:"event"`
+	Attempts       int                    `json:"attempts"`
+	NextAttemptAt  time.Time              `json:"next_attempt_at"`
+	CreatedAt      time.Time              `json:"created_at"`
+}
+
+// snapshot is the durable state written to Config.Path.
+type snapshot struct {
+	Subscriptions []outbound.WebhookSubscription `json:"subscriptions"`
+	Pending       []delivery                     `json:"pending"`
+	Log           []outbound.WebhookAttempt      `json:"log"`
+}
+
+// Service fans transfer events out to webhook subscriptions and delivers them in
+// the background. It is safe for concurrent use.
+type Service struct {
+	cfg    Config
+	client *http.Client
+	logger platform.Logger
+
+	mu       sync.Mutex
+	subs     map[string]*outbound.WebhookSubscription
+	pending  map[string]*delivery
+	inflight map[string]bool
+	breakers map[string]*breaker // by subscription ID
+	log      []outbound.WebhookAttempt
+	version  uint64 // bumped on every change to the durable state
+
+	// writeMu serializes snapshot writes; written is the version on disk.
+	writeMu sync.Mutex
+	written uint64
+	dirty   chan struct{}
+
+	wake     chan struct{}
+	sem      chan struct{}
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+// New loads the durable state at cfg.Path and starts delivering. Provide a context
+// that is cancelled on server shutdown, and call Stop to write the last state;
+// pending deliveries resume on the next start.
+func New(ctx context.Context, cfg Config, logger platform.Logger) (*Service, error) {
+	cfg.defaults()
+	s := &Service{
+		cfg:      cfg,
+		client:   &http.Client{Timeout: cfg.RequestTimeout, Transport: newTransport(cfg.AllowPrivateTargets)},
+		logger:   logger,
+		subs:     make(map[string]*outbound.WebhookSubscription),
+		pending:  make(map[string]*delivery),
+		inflight: make(map[string]bool),
+		breakers: make(map[string]*breaker),
+		dirty:    make(chan struct{}, 1),
+		wake:     make(chan struct{}, 1),
+		sem:      make(chan struct{}, cfg.Concurrency),
+		stopChan: make(chan struct{}),
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	go s.writeLoop(ctx)
+	go func() {
+		t := time.NewTicker(cfg.PollInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+			case <-s.wake:
+			case <-ctx.Done():
+				return
+			case <-s.stopChan:
+				return
+			}
+			s.dispatchDue()
+		}
+	}()
+	return s, nil
+}
+
+// Stop stops background delivery and writes the last state. Background work also
+// stops when ctx is cancelled, but only Stop guarantees the final write.
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() { close(s.stopChan) })
+	if err := s.flush(); err != nil {
+		s.logger.Error(err, platform.Field{Key: "component", Value: "webhook"})
+	}
+}
+
+// Publish implements outbound.EventPublisher. It runs on the request path, so it
+// only queues a delivery for every matching subscription of the event's client;
+// the writer persists them in the background. Deliveries queued after the last
+// write are lost if the process crashes.
+func (s *Service) Publish(ev outbound.TransferEvent) error {
+	now := time.Now().UTC()
+
+	s.mu.Lock()
+	added := 0
+	for _, sub := range s.subs {
+		if sub.ClientID != ev.ClientID || sub.Disabled || !sub.Wants(ev.Type) {
+			continue
+		}
+		id := uuid.NewString()
+		s.pending[id] = &delivery{
+			ID:             id,
+			SubscriptionID: sub.ID,
+			Event:          ev,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		}
+		added++
+	}
+	if added > 0 {
+		s.changed()
+	}
+	s.mu.Unlock()
+
+	if added > 0 {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Subscribe implements outbound.Webhooks. The URL must be http(s) and resolve to
+// public addresses only (see Config.AllowPrivateTargets).
+func (s *Service) Subscribe(clientID, rawURL string, types []outbound.EventType) (outbound.WebhookSubscription, error) {
+	u, err := s.checkTarget(rawURL)
+	if err != nil {
+		return outbound.WebhookSubscription{}, err
+	}
+	for _, t := range types {
+		if t != outbound.EventTransferSettled && t != outbound.EventTransferRejected {
+			return outbound.WebhookSubscription{}, apperr.Invalid(fmt.Sprintf("unknown event type %q", t))
+		}
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return outbound.WebhookSubscription{}, apperr.Wrap(apperr.CodeInternal, "generate webhook secret", err)
+	}
+
+	sub := &outbound.WebhookSubscription{
+		ID:         uuid.NewString(),
+		ClientID:   clientID,
+		URL:        u.String(),
+		Secret:     secret,
+		EventTypes: types,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	s.mu.Lock()
+	s.subs[sub.ID] = sub
+	s.changed()
+	s.mu.Unlock()
+	if err := s.flush(); err != nil {
+		s.mu.Lock()
+		delete(s.subs, sub.ID)
+		s.changed()
+		s.mu.Unlock()
+		return outbound.WebhookSubscription{}, apperr.Wrap(apperr.CodeInternal, "persist webhook subscription", err)
+	}
+	return *sub, nil
+}
+
+// Unsubscribe implements outbound.Webhooks. Pending deliveries are dropped. If the
+// change cannot be persisted, the subscription and its deliveries are restored.
+func (s *Service) Unsubscribe(clientID, id string) (bool, error) {
+	s.mu.Lock()
+	sub, ok := s.subs[id]
+	if !ok || sub.ClientID != clientID {
+		s.mu.Unlock()
+		return false, nil
+	}
+	br, hadBreaker := s.breakers[id]
+	delete(s.subs, id)
+	delete(s.breakers, id)
+	var dropped []*delivery
+	for did, d := range s.pending {
+		if d.SubscriptionID == id {
+			dropped = append(dropped, d)
+			delete(s.pending, did)
+		}
+	}
+	s.changed()
+	s.mu.Unlock()
+	if err := s.flush(); err != nil {
+		s.mu.Lock()
+		s.subs[id] = sub
+		if hadBreaker {
+			s.breakers[id] = br
+		}
+		for _, d := range dropped {
+			s.pending[d.ID] = d
+		}
+		s.changed()
+		s.mu.Unlock()
+		return false, err
+	}
+	return true, nil
+}
+
+// Enable implements outbound.Webhooks. If the change cannot be persisted, the
+// subscription is left as it was.
+func (s *Service) Enable(clientID, id string) (bool, error) {
+	s.mu.Lock()
+	sub, ok := s.subs[id]
+	if !ok || sub.ClientID != clientID {
+		s.mu.Unlock()
+		return false, nil
+	}
+	prev := *sub
+	br, hadBreaker := s.breakers[id]
+	sub.Disabled = false
+	sub.DisabledReason = ""
+	sub.ConsecutiveFailures = 0
+	delete(s.breakers, id)
+	s.changed()
+	s.mu.Unlock()
+	if err := s.flush(); err != nil {
+		s.mu.Lock()
+		sub.Disabled = prev.Disabled
+		sub.DisabledReason = prev.DisabledReason
+		sub.ConsecutiveFailures = prev.ConsecutiveFailures
+		if hadBreaker {
+			s.breakers[id] = br
+		}
+		s.changed()
+		s.mu.Unlock()
+		return false, err
+	}
+	return true, nil
+}
+
+// Subscriptions implements outbound.Webhooks. Oldest first.
+func (s *Service) Subscriptions(clientID string) []outbound.WebhookSubscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []outbound.WebhookSubscription
+	for _, sub := range s.subs {
+		if sub.ClientID == clientID {
+			out = append(out, *sub)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// Deliveries implements outbound.Webhooks.
+func (s *Service) Deliveries(clientID, subscriptionID string, limit int) []outbound.WebhookAttempt {
+	if limit <= 0 || limit > s.cfg.LogSize {
+		limit = s.cfg.LogSize
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []outbound.WebhookAttempt
+	for i := len(s.log) - 1; i >= 0 && len(out) < limit; i-- {
+		a := s.log[i]
+		if a.ClientID != clientID || (subscriptionID != "" && a.SubscriptionID != subscriptionID) {
+			continue
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// attemptJob is a delivery picked for an attempt, with the subscription as it was then.
+type attemptJob struct {
+	d   delivery
+	sub outbound.WebhookSubscription
+}
+
+// dispatchDue starts an attempt for every due delivery whose endpoint breaker allows it.
+func (s *Service) dispatchDue() {
+	now := time.Now().UTC()
+
+	s.mu.Lock()
+	var jobs []attemptJob
+	changed := false
+	for id, d := range s.pending {
+		if s.inflight[id] || d.NextAttemptAt.After(now) {
+			continue
+		}
+		sub, ok := s.subs[d.SubscriptionID]
+		if !ok || sub.Disabled {
+			delete(s.pending, id)
+			s.appendLog(d, sub, 0, "subscription disabled or removed", OutcomeFailed, 0, now)
+			changed = true
+			continue
+		}
+		br := s.breakerFor(sub.ID)
+		if ok, retryAt := br.allow(now); !ok {
+			d.NextAttemptAt = retryAt
+			changed = true
+			continue
+		}
+		s.inflight[id] = true
+		jobs = append(jobs, attemptJob{d: *d, sub: *sub})
+	}
+	if changed {
+		s.changed()
+	}
+	s.mu.Unlock()
+
+	for _, j := range jobs {
+		s.sem <- struct{}{}
+		go func(j attemptJob) {
+			defer func() { <-s.sem }()
+			s.attempt(j)
+		}(j)
+	}
+}
+
+// attempt sends one signed delivery and records the outcome.
+func (s *Service) attempt(j attemptJob) {
+	body, err := json.Marshal(j.d.Event)
+	if err != nil {
+		s.record(j, 0, err, 0)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.RequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.sub.URL, bytes.NewReader(body))
+	if err != nil {
+		s.record(j, 0, err, 0)
+		return
+	}
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderID, j.d.ID)
+	req.Header.Set(HeaderTimestamp, fmt.Sprint(now.Unix()))
+	req.Header.Set(HeaderSignature, Sign(j.sub.Secret, now, body))
+
+	resp, err := s.client.Do(req)
+	latency := time.Since(now)
+	if err != nil {
+		s.record(j, 0, err, latency)
+		return
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		s.record(j, resp.StatusCode, fmt.Errorf("endpoint answered %d", resp.StatusCode), latency)
+		return
+	}
+	s.record(j, resp.StatusCode, nil, latency)
+}
+
+// record applies an attempt's outcome: breaker, retry schedule, auto-disable and log.
+func (s *Service) record(j attemptJob, code int, attemptErr error, latency time.Duration) {
+	now := time.Now().UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.inflight, j.d.ID)
+
+	d, ok := s.pending[j.d.ID]
+	if !ok {
+		return // unsubscribed meanwhile
+	}
+	d.Attempts++
+	sub := s.subs[d.SubscriptionID]
+	br := s.breakerFor(d.SubscriptionID)
+
+	var outcome, errMsg string
+	switch {
+	case attemptErr == nil:
+		br.success()
+		if sub != nil {
+			sub.ConsecutiveFailures = 0
+		}
+		delete(s.pending, d.ID)
+		outcome = OutcomeDelivered
+	default:
+		errMsg = attemptErr.Error()
+		br.failure(now, s.cfg.BreakerThreshold, s.cfg.BreakerCooldown)
+		if sub != nil {
+			sub.ConsecutiveFailures++
+			if !sub.Disabled && sub.ConsecutiveFailures >= s.cfg.DisableAfter {
+				sub.Disabled = true
+				sub.DisabledReason = fmt.Sprintf("disabled after %d consecutive failures", sub.ConsecutiveFailures)
+				s.logger.Warn("webhook endpoint disabled",
+					platform.Field{Key: "subscription_id", Value: sub.ID},
+					platform.Field{Key: "client_id", Value: sub.ClientID},
+					platform.Field{Key: "url", Value: sub.URL},
+				)
+			}
+		}
+		if d.Attempts >= s.cfg.MaxAttempts {
+			delete(s.pending, d.ID)
+			outcome = OutcomeFailed
+		} else {
+			d.NextAttemptAt = now.Add(backoff.FullJitter(d.Attempts, s.cfg.BaseBackoff, s.cfg.MaxBackoff) + time.Millisecond)
+			outcome = OutcomeRetrying
+		}
+	}
+	s.appendLog(d, sub, code, errMsg, outcome, latency, now)
+	s.changed()
+}
+
+// breakerFor returns the endpoint breaker of a subscription. Caller must hold s.mu.
+func (s *Service) breakerFor(subID string) *breaker {
+	br, ok := s.breakers[subID]
+	if !ok {
+		br = &breaker{}
+		s.breakers[subID] = br
+	}
+	return br
+}
+
+// appendLog records an attempt, keeping the newest LogSize entries. Caller must hold s.mu.
+func (s *Service) appendLog(d *delivery, sub *outbound.WebhookSubscription, code int, errMsg, outcome string, latency time.Duration, at time.Time) {
+	a := outbound.WebhookAttempt{
+		DeliveryID:     d.ID,
+		ClientID:       d.Event.ClientID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.Event.ID,
+		EventType:      d.Event.Type,
+		Attempt:        d.Attempts,
+		StatusCode:     code,
+		Error:          errMsg,
+		Outcome:        outcome,
+		LatencyMs:      latency.Milliseconds(),
+		At:             at,
+	}
+	if sub != nil {
+		a.ClientID = sub.ClientID
+	}
+	s.log = append(s.log, a)
+	if over := len(s.log) - s.cfg.LogSize; over > 0 {
+		s.log = append(s.log[:0:0], s.log[over:]...)
+	}
+}
+
+// load reads the snapshot file, if any.
+func (s *Service) load() error {
+	if s.cfg.Path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.cfg.Path), 0o755); err != nil {
+		return fmt.Errorf("webhook dir: %w", err)
+	}
+	b, err := os.ReadFile(s.cfg.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read webhooks: %w", err)
+	}
+	var snap snapshot
+	if err := json.Unmarshal(b, &snap); err != nil {
+		return fmt.Errorf("decode webhooks: %w", err)
+	}
+	for i := range snap.Subscriptions {
+		sub := snap.Subscriptions[i]
+		s.subs[sub.ID] = &sub
+	}
+	for i := range snap.Pending {
+		d := snap.Pending[i]
+		s.pending[d.ID] = &d
+	}
+	s.log = snap.Log
+	return nil
+}
+
+// changed marks the durable state as modified and wakes the writer. Caller must
+// hold s.mu.
+func (s *Service) changed() {
+	s.version++
+	select {
+	case s.dirty <- struct{}{}:
+	default:
+	}
+}
+
+// writeLoop is the single background writer: it persists the state after changes,
+// so delivery bookkeeping never waits on the disk. Changes made during a write are
+// coalesced into the next one.
+func (s *Service) writeLoop(ctx context.Context) {
+	for {
+		select {
+		case <-s.dirty:
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		}
+		if err := s.flush(); err != nil {
+			s.logger.Error(err, platform.Field{Key: "component", Value: "webhook"})
+		}
+	}
+}
+
+// flush replaces the snapshot file (subscriptions, pending deliveries and the
+// delivery log) in one atomic write, unless it already holds the latest version.
+// The state is copied under s.mu and encoded and written outside it; holding
+// writeMu throughout keeps the writes in version order. Without a Path the
+// service runs in memory only.
+func (s *Service) flush() error {
+	if s.cfg.Path == "" {
+		return nil
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.mu.Lock()
+	version := s.version
+	if version == s.written {
+		s.mu.Unlock()
+		return nil
+	}
+	snap := snapshot{Log: append([]outbound.WebhookAttempt(nil), s.log...)}
+	for _, sub := range s.subs {
+		snap.Subscriptions = append(snap.Subscriptions, *sub)
+	}
+	for _, d := range s.pending {
+		snap.Pending = append(snap.Pending, *d)
+	}
+	s.mu.Unlock()
+
+	b, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("encode webhooks: %w", err)
+	}
+
+	if err := file_kit.WriteAtomic(s.cfg.Path, b, 0o600); err != nil {
+		return fmt.Errorf("persist webhooks: %w", err)
+	}
+	s.written = version
+	return nil
+}
@@ -0,0 +1,145 @@
+package webhook
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	zap_adapter "fintech-capstone/m/v2/internal/platform/adapters/zap"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+
+	"go.uber.org/zap"
+)
+
+func TestPublicAddr(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"203.0.113.10":        true,
+		"2001:db8::1":         true,
+		"127.0.0.1":           false,
+		"::1":                 false,
+		"169.254.169.254":     false, // cloud metadata
+		"fe80::1":             false,
+		"10.1.2.3":            false,
+		"172.16.0.1":          false,
+		"192.168.1.1":         false,
+		"fd00::1":             false,
+		"100.64.0.1":          false,
+		"0.0.0.0":             false,
+		"::ffff:127.0.0.1":    false,
+		"::ffff:203.0.113.10": true,
+		"224.0.0.1":           false,
+		"255.255.255.255":     false,
+	} {
+		if got := publicAddr(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("publicAddr(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestSubscribeRefusesInternalTargets(t *testing.T) {
+	s, err := New(t.Context(), Config{}, zap_adapter.New(zap.NewNop()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(s.Stop)
+
+	for _, u := range []string{
+		"ftp://203.0.113.10/hook",
+		"http:///hook",
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"https://[::1]/hook",
+		"http://10.0.0.5/hook",
+	} {
+		if _, err := s.Subscribe("alice", u, nil); apperr.As(err).Code != apperr.CodeInvalid {
+			t.Errorf("Subscribe(%s) = %v, want invalid", u, err)
+		}
+	}
+	if _, err := s.Subscribe("alice", "https://203.0.113.10/hook", nil); err != nil {
+		t.Fatalf("public target refused: %v", err)
+	}
+}
+
+func TestStopWritesQueuedDeliveries(t *testing.T) {
+	cfg := Config{Path: filepath.Join(t.TempDir(), "webhooks.json"), PollInterval: time.Hour, BaseBackoff: time.Hour}
+	s, err := New(t.Context(), cfg, zap_adapter.New(zap.NewNop()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub, err := s.Subscribe("alice", "https://203.0.113.10/hook", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Stopped first, so nothing is attempted and every delivery stays pending.
+	s.Stop()
+	for range 3 {
+		if err := s.Publish(outbound.TransferEvent{ID: "ev", ClientID: "alice", Type: outbound.EventTransferSettled}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Stop()
+
+	reloaded, err := New(t.Context(), cfg, zap_adapter.New(zap.NewNop()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(reloaded.Stop)
+	if subs := reloaded.Subscriptions("alice"); len(subs) != 1 || subs[0].ID != sub.ID {
+		t.Fatalf("subscriptions = %+v, want %s", subs, sub.ID)
+	}
+	if n := len(reloaded.pending); n != 3 {
+		t.Fatalf("pending = %d, want 3", n)
+	}
+}
+
+func TestUnsubscribeAndEnableRollBackWhenTheWriteFails(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "state")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(t.Context(), Config{Path: filepath.Join(dir, "webhooks.json"), PollInterval: time.Hour}, zap_adapter.New(zap.NewNop()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub, err := s.Subscribe("alice", "https://203.0.113.10/hook", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Stopped first, so the delivery stays pending and only the calls below write.
+	s.Stop()
+	if err := s.Publish(outbound.TransferEvent{ID: "ev", ClientID: "alice", Type: outbound.EventTransferSettled}); err != nil {
+		t.Fatal(err)
+	}
+	s.mu.Lock()
+	s.subs[sub.ID].Disabled = true
+	s.subs[sub.ID].DisabledReason = "disabled after 5 consecutive failures"
+	s.changed()
+	s.mu.Unlock()
+	// The state directory becomes a file, so every write fails.
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dir, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := s.Enable("alice", sub.ID); ok || err == nil {
+		t.Fatalf("Enable = %v, %v, want a write error", ok, err)
+	}
+	if subs := s.Subscriptions("alice"); len(subs) != 1 || !subs[0].Disabled || subs[0].DisabledReason == "" {
+		t.Fatalf("subscriptions after a failed enable = %+v, want still disabled", subs)
+	}
+	if ok, err := s.Unsubscribe("alice", sub.ID); ok || err == nil {
+		t.Fatalf("Unsubscribe = %v, %v, want a write error", ok, err)
+	}
+	if subs := s.Subscriptions("alice"); len(subs) != 1 || subs[0].ID != sub.ID {
+		t.Fatalf("subscriptions after a failed unsubscribe = %+v, want %s kept", subs, sub.ID)
+	}
+	if n := len(s.pending); n != 1 {
+		t.Fatalf("pending after a failed unsubscribe = %d, want 1", n)
+	}
+}
@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+
+	"fintech-capstone/m/v2/internal/platform/apperr"
+)
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), internal to
+// providers like the private ranges but not covered by netip.Addr.IsPrivate.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// publicAddr reports whether a webhook may be delivered to addr: a global unicast
+// address outside the private and shared ranges. This keeps clients from making
+// the gateway call loopback, link-local (cloud metadata) or internal services.
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
+
+// checkTarget parses a subscription URL and, unless private targets are allowed,
+// resolves its host and refuses it if any address is not public.
+func (s *Service) checkTarget(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return nil, apperr.Invalid("webhook url must be an absolute http(s) url")
+	}
+	if s.cfg.AllowPrivateTargets {
+		return u, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.RequestTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return nil, apperr.Invalid(fmt.Sprintf("webhook host %q does not resolve", u.Hostname()))
+	}
+	for _, addr := range addrs {
+		if !publicAddr(addr) {
+			return nil, apperr.Invalid(fmt.Sprintf("webhook host %q resolves to a non-public address", u.Hostname()))
+		}
+	}
+	return u, nil
+}
+
+// newTransport returns the transport for deliveries. Unless private targets are
+// allowed, it checks every address it connects to, so a host that later resolves
+// to an internal address (DNS rebinding) or a redirect to one is refused too.
+// Proxies are not used: the check must see the endpoint's own address.
+func newTransport(allowPrivate bool) http.RoundTripper {
+	if allowPrivate {
+		return http.DefaultTransport
+	}
+	dialer := &net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			ap, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !publicAddr(ap.Addr()) {
+				return fmt.Errorf("webhook target %s is not a public address", ap.Addr())
+			}
+			return nil
+		},
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = nil
+	t.DialContext = dialer.DialContext
+	return t
+}
@@ -0,0 +1,150 @@
+package worker_pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"fintech-capstone/m/v2/internal/api_gateway/contracts"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+)
+
+// Compile-time checks that *Bulkheads implements the dispatcher ports.
+var (
+	_ outbound.Dispatcher       = (*Bulkheads)(nil)
+	_ outbound.BulkheadReporter = (*Bulkheads)(nil)
+	_ outbound.Drainer          = (*Bulkheads)(nil)
+)
+
+// BulkheadConfig names one isolated pool and sizes its queues and workers.
+type BulkheadConfig struct {
+	Name string
+	Pool Config
+}
+
+// Bulkheads routes jobs into independent pools by account segment.
+// Each pool has its own workers and queue bounds, so a slow or hot segment
+// saturates (and is rejected with apperr.Overloaded) without starving the others.
+// It is safe for concurrent use.
+type Bulkheads struct {
+	segment  Segmenter
+	pools    map[string]*Pool
+	order    []string // config order, for stable iteration
+	fallback *Pool
+}
+
+// NewBulkheads creates one Pool per bulkhead, all running exec. Jobs whose segment has
+// no bulkhead go to the first one configured. Cancel ctx to stop every pool.
+func NewBulkheads(ctx context.Context, segment Segmenter, bulkheads []BulkheadConfig, exec Executor) *Bulkheads {
+	if len(bulkheads) == 0 {
+		bulkheads = []BulkheadConfig{{Name: "default"}}
+	}
+	b := &Bulkheads{
+		segment: segment,
+		pools:   make(map[string]*Pool, len(bulkheads)),
+	}
+	for _, bc := range bulkheads {
+		p := New(ctx, bc.Pool, exec)
+		b.pools[bc.Name] = p
+		b.order = append(b.order, bc.Name)
+		if b.fallback == nil {
+			b.fallback = p
+		}
+	}
+	return b
+}
+
+// Submit implements outbound.Dispatcher.
+func (b *Bulkheads) Submit(ctx context.Context, cmd inbound.TransferCommand) (inbound.TransferResult, error) {
+	return b.poolFor(cmd).Submit(ctx, cmd)
+}
+
+// QueueDepth implements outbound.Dispatcher.
+func (b *Bulkheads) QueueDepth() int64 {
+	var n int64
+	for _, p := range b.pools {
+		n += p.QueueDepth()
+	}
+	return n
+}
+
+// QueueDepthByClass implements outbound.Dispatcher.
+func (b *Bulkheads) QueueDepthByClass() map[outbound.PriorityClass]int64 {
+	out := make(map[outbound.PriorityClass]int64)
+	for _, p := range b.pools {
+		for class, depth := range p.QueueDepthByClass() {
+			out[class] += depth
+		}
+	}
+	return out
+}
+
+// ActiveWorkers implements outbound.Dispatcher.
+func (b *Bulkheads) ActiveWorkers() int64 {
+	var n int64
+	for _, p := range b.pools {
+		n += p.ActiveWorkers()
+	}
+	return n
+}
+
+// Bulkheads implements outbound.BulkheadReporter.
+func (b *Bulkheads) Bulkheads() map[string]contracts.BulkheadSnapshot {
+	out := make(map[string]contracts.BulkheadSnapshot, len(b.pools))
+	for _, name := range b.order {
+		st := b.pools[name].Stats()
+		out[name] = contracts.BulkheadSnapshot{
+			Workers:       st.Workers,
+			ActiveWorkers: st.ActiveWorkers,
+			QueueDepth:    st.QueueDepth,
+			QueueCapacity: st.QueueCapacity,
+			Rejected:      st.Rejected,
+			Shed:          st.Shed,
+		}
+	}
+	return out
+}
+
+// Stop stops every pool.
+func (b *Bulkheads) Stop() {
+	for _, p := range b.pools {
+		p.Stop()
+	}
+}
+
+// Drain implements outbound.Drainer by draining every pool in parallel.
+func (b *Bulkheads) Drain(ctx context.Context) ([]outbound.PendingJob, error) {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		pending []outbound.PendingJob
+		errs    []error
+	)
+	for _, name := range b.order {
+		wg.Add(1)
+		go func(name string, p *Pool) {
+			defer wg.Done()
+			jobs, err := p.Drain(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			pending = append(pending, jobs...)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("bulkhead %s: %w", name, err))
+			}
+		}(name, b.pools[name])
+	}
+	wg.Wait()
+	return sortPending(pending), errors.Join(errs...)
+}
+
+// poolFor resolves the bulkhead for cmd, falling back to the first bulkhead.
+func (b *Bulkheads) poolFor(cmd inbound.TransferCommand) *Pool {
+	if b.segment != nil {
+		if p, ok := b.pools[b.segment(cmd)]; ok {
+			return p
+		}
+	}
+	return b.fallback
+}
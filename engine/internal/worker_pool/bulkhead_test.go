@@ -0,0 +1,74 @@
+package worker_pool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+)
+
+func TestBulkheadsIsolateASaturatedSegment(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	small := Config{Workers: 1, Classes: []ClassConfig{{Class: "interactive", Weight: 1, QueueSize: 1}}}
+	b := NewBulkheads(t.Context(),
+		PrefixSegments(map[string]string{"HOT-": "hot"}, "cold"),
+		[]BulkheadConfig{{Name: "cold", Pool: small}, {Name: "hot", Pool: small}},
+		func(_ context.Context, cmd inbound.TransferCommand) (inbound.TransferResult, error) {
+			if strings.HasPrefix(cmd.FromAccount(), "HOT-") {
+				<-release // a stuck downstream for this segment only
+			}
+			return inbound.TransferResult{}, nil
+		})
+	t.Cleanup(b.Stop)
+
+	// Fill the hot bulkhead: one job on its worker, then one in its queue.
+	go b.Submit(t.Context(), inbound.NewTransferCommand("HOT-1", "b", 100, "k1"))
+	for b.Bulkheads()["hot"].ActiveWorkers < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	go b.Submit(t.Context(), inbound.NewTransferCommand("HOT-1", "b", 100, "k2"))
+	for b.Bulkheads()["hot"].QueueDepth < 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, err := b.Submit(t.Context(), inbound.NewTransferCommand("HOT-2", "b", 100, "k")); apperr.As(err).Code != apperr.CodeOverloaded {
+		t.Fatalf("full hot bulkhead accepted a job: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+	defer cancel()
+	for i := 0; i < 5; i++ {
+		if _, err := b.Submit(ctx, inbound.NewTransferCommand("COLD-1", "b", 100, fmt.Sprint(i))); err != nil {
+			t.Fatalf("cold bulkhead held up by the hot one: %v", err)
+		}
+	}
+
+	snap := b.Bulkheads()
+	if snap["hot"].Rejected != 1 || snap["cold"].Rejected != 0 {
+		t.Fatalf("rejected: hot %d, cold %d; want 1, 0", snap["hot"].Rejected, snap["cold"].Rejected)
+	}
+}
+
+func TestHashSegmentsKeepsAnAccountInOneSegment(t *testing.T) {
+	names := []string{"accounts-0", "accounts-1", "accounts-2", "accounts-3"}
+	seg := HashSegments(names...)
+	used := make(map[string]bool)
+	for i := 0; i < 200; i++ {
+		cmd := inbound.NewTransferCommand(fmt.Sprintf("acc-%d", i), "b", 100, "k")
+		name := seg(cmd)
+		if again := seg(inbound.NewTransferCommand(cmd.FromAccount(), "c", 5, "k2")); again != name {
+			t.Fatalf("%s went to %s, then %s", cmd.FromAccount(), name, again)
+		}
+		used[name] = true
+	}
+	if len(used) != len(names) {
+		t.Fatalf("200 accounts used segments %v, want all of %v", used, names)
+	}
+	if got := HashSegments()(inbound.NewTransferCommand("a", "b", 100, "k")); got != "" {
+		t.Fatalf("no segments routed to %q, want the fallback", got)
+	}
+}
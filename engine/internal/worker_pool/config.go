@@ -0,0 +1,50 @@
+package worker_pool
+
+import (
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+)
+
+// ClassConfig controls one priority class queue.
+type ClassConfig struct {
+	Class outbound.PriorityClass
+	// Weight is the class's share of worker picks relative to other busy classes. <= 0 => 1.
+	Weight int
+	// QueueSize bounds the jobs waiting in this class. <= 0 => 256.
+	QueueSize int
+}
+
+// Config combines all options.
+type Config struct {
+	Workers int           // <= 0 => runtime.NumCPU()
+	Classes []ClassConfig // nil => DefaultClasses
+	// DefaultClass is used for jobs without (or with an unknown) class. "" => first class.
+	DefaultClass outbound.PriorityClass
+	// Shedding drops jobs that queued too long under overload. Zero => plain FIFO.
+	Shedding ShedConfig
+}
+
+// ShedConfig tunes CoDel-style load shedding. The pool counts as overloaded once its
+// queues have not been empty for a whole Interval, i.e. a standing queue rather than
+// a burst. While overloaded, classes are served newest first (adaptive LIFO) and a
+// job that waited longer than its class target is dropped with a retryable error.
+// A job whose remaining deadline is shorter than the typical run time is dropped
+// at any time: it would only be cancelled half way.
+type ShedConfig struct {
+	// Target is the queue wait the heaviest class tolerates under overload. Lighter
+	// classes get Target scaled by their weight, so they are shed first.
+	// <= 0 => shedding disabled.
+	Target time.Duration
+	// Interval is how long the queues must stay non-empty to count as overloaded. <= 0 => 100ms.
+	Interval time.Duration
+	// RetryAfter is the hint given to shed callers. <= 0 => 1s.
+	RetryAfter time.Duration
+}
+
+// DefaultClasses serves interactive transfers ahead of bulk payouts ahead of internal sweeps.
+var DefaultClasses = []ClassConfig{
+	{Class: outbound.PriorityInteractive, Weight: 8, QueueSize: 1024},
+	{Class: outbound.PriorityBulk, Weight: 3, QueueSize: 1024},
+	{Class: outbound.PriorityInternal, Weight: 1, QueueSize: 256},
+}
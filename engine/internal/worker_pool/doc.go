@@ -0,0 +1,24 @@
+// Package worker_pool provides a bounded, concurrency-safe worker pool that
+// implements the outbound.Dispatcher interface used by the transfer use case.
+//
+// Design goals:
+//   - Bounded queues (no unbounded goroutines); a full queue rejects fast.
+//   - Priority classes with weighted fair scheduling: every class is served in
+//     proportion to its weight, so low-priority work is slowed, never starved.
+//   - Bulkheads: independent pools per account segment (hash range, account
+//     type, tenant), each with its own workers and queue bounds. A saturated
+//     bulkhead rejects fast with apperr.Overloaded instead of borrowing workers.
+//   - Ordered execution: Mailboxes keep per-account FIFO (actor-style) on top of
+//     any dispatcher, while distinct accounts still run in parallel. Idle
+//     mailboxes are evicted after a TTL, like idle limiter client buckets.
+//   - Cancellation aware: a job whose caller gave up is skipped, not executed.
+//   - Load shedding: under a standing queue the pool serves newest first and
+//     drops jobs that waited past their class target (lighter classes first), or
+//     whose deadline is too close to run, with a retryable apperr.Overloaded.
+//   - Graceful drain: Drain stops intake, lets running jobs finish and hands
+//     queued ones back unstarted, in submission order, to be persisted.
+//   - Cheap observability: queue depth (total and per class) and active workers.
+//
+// The pool does not know how to move money. It runs an Executor supplied by the
+// caller (e.g. a ledger client) on its workers.
+package worker_pool
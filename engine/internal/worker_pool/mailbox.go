@@ -0,0 +1,292 @@
+package worker_pool
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/contracts"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+)
+
+// Compile-time checks that *Mailboxes implements the dispatcher ports.
+var (
+	_ outbound.Dispatcher       = (*Mailboxes)(nil)
+	_ outbound.BulkheadReporter = (*Mailboxes)(nil)
+	_ outbound.Drainer          = (*Mailboxes)(nil)
+)
+
+// MailboxConfig controls per-key ordered execution.
+type MailboxConfig struct {
+	// Key picks the mailbox for a job. nil => source account.
+	Key func(cmd inbound.TransferCommand) string
+	// MailboxSize bounds the jobs waiting per key. <= 0 => 64.
+	MailboxSize int
+	// TTL evicts idle mailboxes after this duration. <= 0 => 10m.
+	TTL time.Duration
+	// NumShards splits the mailbox map to reduce lock contention. <= 0 => 64.
+	NumShards       int
+	CleanupInterval time.Duration // <= 0 => 1m
+}
+
+// mailbox is the FIFO of one key. All fields are guarded by the shard mutex.
+type mailbox struct {
+	jobs     []*job
+	running  bool // a drain goroutine owns this mailbox
+	lastSeen time.Time
+}
+
+type mailboxShard struct {
+	mu   sync.Mutex
+	data map[string]*mailbox
+}
+
+// Mailboxes gives actor-style, per-key FIFO execution on top of another dispatcher.
+// Jobs with the same key (by default the source account) reach next one at a time,
+// in the order they were accepted; distinct keys still run in parallel. A drain
+// goroutine only exists while its mailbox has work, and idle mailboxes are evicted
+// after TTL. It is safe for concurrent use.
+type Mailboxes struct {
+	next   outbound.Dispatcher
+	cfg    MailboxConfig
+	shards []mailboxShard
+
+	closed     atomic.Bool
+	drains     sync.WaitGroup // drain goroutines; Add under the shard mutex
+	leftoverMu sync.Mutex
+	leftover   []outbound.PendingJob // jobs next refused or handed back while draining
+
+	cleanupTicker   *time.Ticker
+	stopCleanupChan chan struct{}
+	stopOnce        sync.Once
+}
+
+// NewMailboxes wraps next with per-key ordering. Provide a context that is cancelled
+// on server shutdown to stop background cleanup.
+func NewMailboxes(ctx context.Context, cfg MailboxConfig, next outbound.Dispatcher) *Mailboxes {
+	if cfg.Key == nil {
+		cfg.Key = func(cmd inbound.TransferCommand) string { return cmd.FromAccount() }
+	}
+	if cfg.MailboxSize <= 0 {
+		cfg.MailboxSize = 64
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = 10 * time.Minute
+	}
+	if cfg.NumShards <= 0 {
+		cfg.NumShards = 64
+	}
+	if cfg.CleanupInterval <= 0 {
+		cfg.CleanupInterval = time.Minute
+	}
+
+	m := &Mailboxes{
+		next:            next,
+		cfg:             cfg,
+		shards:          make([]mailboxShard, cfg.NumShards),
+		stopCleanupChan: make(chan struct{}),
+	}
+	for i := range m.shards {
+		m.shards[i].data = make(map[string]*mailbox, 256)
+	}
+
+	// Background janitor: evict idle mailboxes to bound memory.
+	m.cleanupTicker = time.NewTicker(cfg.CleanupInterval)
+	go func() {
+		defer m.cleanupTicker.Stop()
+		for {
+			select {
+			case <-m.cleanupTicker.C:
+				m.cleanup(cfg.TTL)
+			case <-ctx.Done():
+				return
+			case <-m.stopCleanupChan:
+				return
+			}
+		}
+	}()
+	return m
+}
+
+// Stop stops the background cleanup goroutine (optional; otherwise it exits when ctx is cancelled).
+func (m *Mailboxes) Stop() { m.stopOnce.Do(func() { close(m.stopCleanupChan) }) }
+
+// Submit implements outbound.Dispatcher.
+func (m *Mailboxes) Submit(ctx context.Context, cmd inbound.TransferCommand) (inbound.TransferResult, error) {
+	key := m.cfg.Key(cmd)
+	sh := m.getShard(key)
+	j := newJob(ctx, cmd)
+
+	sh.mu.Lock()
+	if m.closed.Load() {
+		sh.mu.Unlock()
+		return inbound.TransferResult{}, shutdownErr()
+	}
+	mb := sh.data[key]
+	if mb == nil {
+		mb = &mailbox{}
+		sh.data[key] = mb
+	}
+	if len(mb.jobs) >= m.cfg.MailboxSize {
+		sh.mu.Unlock()
+		return inbound.TransferResult{}, apperr.Overloaded("too many pending transfers for account")
+	}
+	mb.jobs = append(mb.jobs, j)
+	mb.lastSeen = time.Now()
+	start := !mb.running
+	mb.running = true
+	if start {
+		m.drains.Add(1)
+	}
+	sh.mu.Unlock()
+
+	if start {
+		go m.drain(sh, mb)
+	}
+	return await(ctx, j)
+}
+
+// QueueDepth implements outbound.Dispatcher. It counts jobs waiting in mailboxes
+// as well as jobs already handed to next.
+func (m *Mailboxes) QueueDepth() int64 {
+	var n int64
+	for i := range m.shards {
+		sh := &m.shards[i]
+		sh.mu.Lock()
+		for _, mb := range sh.data {
+			n += int64(len(mb.jobs))
+		}
+		sh.mu.Unlock()
+	}
+	return n + m.next.QueueDepth()
+}
+
+// QueueDepthByClass implements outbound.Dispatcher.
+// Jobs get a class once they reach next, so only next's queues are reported.
+func (m *Mailboxes) QueueDepthByClass() map[outbound.PriorityClass]int64 {
+	return m.next.QueueDepthByClass()
+}
+
+// ActiveWorkers implements outbound.Dispatcher.
+func (m *Mailboxes) ActiveWorkers() int64 { return m.next.ActiveWorkers() }
+
+// Bulkheads implements outbound.BulkheadReporter by forwarding to next, if it has bulkheads.
+func (m *Mailboxes) Bulkheads() map[string]contracts.BulkheadSnapshot {
+	if br, ok := m.next.(outbound.BulkheadReporter); ok {
+		return br.Bulkheads()
+	}
+	return nil
+}
+
+// Drain implements outbound.Drainer. Jobs still waiting in mailboxes are handed
+// back without running, as are jobs next refuses or hands back, in submission order;
+// their callers are told they were accepted. Jobs whose callers already gave up
+// are dropped instead (see job.handBack).
+func (m *Mailboxes) Drain(ctx context.Context) ([]outbound.PendingJob, error) {
+	m.closed.Store(true)
+
+	var handedBack []*job
+	for i := range m.shards {
+		sh := &m.shards[i]
+		sh.mu.Lock()
+		for _, mb := range sh.data {
+			handedBack = append(handedBack, mb.jobs...)
+			mb.jobs = nil
+		}
+		sh.mu.Unlock()
+	}
+	var pending []outbound.PendingJob
+	for _, j := range handedBack {
+		if pj, ok := j.handBack(""); ok {
+			pending = append(pending, pj)
+		}
+	}
+
+	var errs []error
+	if d, ok := m.next.(outbound.Drainer); ok {
+		jobs, err := d.Drain(ctx)
+		pending = append(pending, jobs...)
+		errs = append(errs, err)
+	}
+
+	// Let drain goroutines finish handing their last job to next.
+	idle := make(chan struct{})
+	go func() {
+		m.drains.Wait()
+		close(idle)
+	}()
+	select {
+	case <-idle:
+	case <-ctx.Done():
+		errs = append(errs, ctx.Err())
+	}
+
+	m.leftoverMu.Lock()
+	pending = append(pending, m.leftover...)
+	m.leftover = nil
+	m.leftoverMu.Unlock()
+	return sortPending(pending), errors.Join(errs...)
+}
+
+// drain hands the mailbox's jobs to next one at a time until it is empty.
+func (m *Mailboxes) drain(sh *mailboxShard, mb *mailbox) {
+	defer m.drains.Done()
+	for {
+		sh.mu.Lock()
+		if len(mb.jobs) == 0 {
+			mb.running = false
+			mb.lastSeen = time.Now()
+			sh.mu.Unlock()
+			return
+		}
+		j := mb.jobs[0]
+		mb.jobs[0] = nil
+		mb.jobs = mb.jobs[1:]
+		sh.mu.Unlock()
+
+		// The caller already gave up; don't let it hold up the rest of the mailbox.
+		if err := j.ctx.Err(); err != nil {
+			j.done <- outcome{err: err}
+			continue
+		}
+		res, err := m.next.Submit(j.ctx, j.cmd)
+		if m.closed.Load() && notRun(err) {
+			// next is shutting down and never started the job: keep it for resume.
+			if pj, ok := j.handBack(""); ok {
+				m.leftoverMu.Lock()
+				m.leftover = append(m.leftover, pj)
+				m.leftoverMu.Unlock()
+			}
+			continue
+		}
+		j.done <- outcome{res: res, err: err}
+	}
+}
+
+func (m *Mailboxes) getShard(key string) *mailboxShard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return &m.shards[h.Sum32()%uint32(len(m.shards))]
+}
+
+// cleanup evicts idle, empty mailboxes across shards.
+func (m *Mailboxes) cleanup(ttl time.Duration) (evicted int) {
+	now := time.Now()
+	for i := range m.shards {
+		sh := &m.shards[i]
+		sh.mu.Lock()
+		for key, mb := range sh.data {
+			if !mb.running && len(mb.jobs) == 0 && now.Sub(mb.lastSeen) >= ttl {
+				delete(sh.data, key)
+				evicted++
+			}
+		}
+		sh.mu.Unlock()
+	}
+	return
+}
@@ -0,0 +1,138 @@
+package worker_pool
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+)
+
+// waiting reports how many jobs wait in key's mailbox, and whether it exists.
+func waiting(m *Mailboxes, key string) (int, bool) {
+	sh := m.getShard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if mb := sh.data[key]; mb != nil {
+		return len(mb.jobs), true
+	}
+	return 0, false
+}
+
+func TestMailboxesRunEachAccountInAcceptanceOrder(t *testing.T) {
+	const accounts, perAccount = 8, 25
+	gate := make(chan struct{})
+	var (
+		mu      sync.Mutex
+		running = make(map[string]int)
+		ran     = make(map[string][]int64)
+		overlap []string
+	)
+	pool := New(t.Context(), Config{Workers: 4}, func(_ context.Context, cmd inbound.TransferCommand) (inbound.TransferResult, error) {
+		<-gate
+		mu.Lock()
+		running[cmd.FromAccount()]++
+		if running[cmd.FromAccount()] > 1 {
+			overlap = append(overlap, cmd.FromAccount())
+		}
+		ran[cmd.FromAccount()] = append(ran[cmd.FromAccount()], cmd.AmountCents())
+		mu.Unlock()
+
+		time.Sleep(100 * time.Microsecond)
+		mu.Lock()
+		running[cmd.FromAccount()]--
+		mu.Unlock()
+		return inbound.TransferResult{}, nil
+	})
+	t.Cleanup(pool.Stop)
+	m := NewMailboxes(t.Context(), MailboxConfig{MailboxSize: perAccount}, pool)
+	t.Cleanup(m.Stop)
+
+	// Accounts submit concurrently; within an account each job is accepted
+	// (waiting in the mailbox) before the next one is submitted.
+	var wg, accepted sync.WaitGroup
+	errs := make(chan error, accounts*perAccount)
+	for a := 0; a < accounts; a++ {
+		account := fmt.Sprintf("acc-%d", a)
+		wg.Add(1)
+		accepted.Add(1)
+		go func() {
+			defer wg.Done()
+			var submits sync.WaitGroup
+			for i := 1; i <= perAccount; i++ {
+				submits.Add(1)
+				go func() {
+					defer submits.Done()
+					if _, err := m.Submit(t.Context(), inbound.NewTransferCommand(account, "b", int64(i), fmt.Sprint(i))); err != nil {
+						errs <- err
+					}
+				}()
+				// The drain goroutine takes the first job and waits on it; the
+				// rest stay in the mailbox.
+				for n, ok := waiting(m, account); !ok || n != i-1; n, ok = waiting(m, account) {
+					time.Sleep(50 * time.Microsecond)
+				}
+			}
+			accepted.Done()
+			submits.Wait()
+		}()
+	}
+	accepted.Wait()
+	close(gate)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatal(err)
+	}
+	if len(overlap) > 0 {
+		t.Fatalf("accounts ran two jobs at once: %v", overlap)
+	}
+	for account, amounts := range ran {
+		for i, amount := range amounts {
+			if amount != int64(i+1) {
+				t.Fatalf("%s ran %v, want 1..%d in order", account, amounts, perAccount)
+			}
+		}
+	}
+	if len(ran) != accounts {
+		t.Fatalf("%d accounts ran, want %d", len(ran), accounts)
+	}
+}
+
+func TestMailboxesDrainDropsJobsWhoseCallerGaveUp(t *testing.T) {
+	m := NewMailboxes(t.Context(), MailboxConfig{}, heldPool(t, Config{}))
+	t.Cleanup(m.Stop)
+	gone, leave := context.WithCancel(t.Context())
+	answered := make(chan error, 3)
+	for i, tc := range []struct {
+		ctx context.Context
+		key string
+	}{{t.Context(), "first"}, {gone, "gone"}, {t.Context(), "waiting"}} {
+		go func() {
+			_, err := m.Submit(tc.ctx, inbound.NewTransferCommand("acc-1", "b", 100, tc.key))
+			answered <- err
+		}()
+		// The first job moves on to the pool; the others wait in the mailbox.
+		for n, ok := waiting(m, "acc-1"); !ok || n != i; n, ok = waiting(m, "acc-1") {
+			time.Sleep(50 * time.Microsecond)
+		}
+	}
+	leave()
+	if err := <-answered; err == nil {
+		t.Fatal("a caller that left got no error")
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	pending, _ := m.Drain(ctx) // the pool's held job is still running
+	var keys []string
+	for _, pj := range pending {
+		keys = append(keys, pj.IdempotencyKey)
+	}
+	if fmt.Sprint(keys) != "[first waiting]" {
+		t.Fatalf("handed back %v, want [first waiting]", keys)
+	}
+}
@@ -0,0 +1,352 @@
+package worker_pool
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+)
+
+// Compile-time checks that *Pool implements the dispatcher ports.
+var (
+	_ outbound.Dispatcher = (*Pool)(nil)
+	_ outbound.Drainer    = (*Pool)(nil)
+)
+
+// Executor performs a single transfer on a worker goroutine. ctx is the caller's
+// request context: an executor must stop waiting (e.g. for ledger locks) once it is
+// done, and must not commit after that, because the caller was already answered.
+//
+// Failures the same transfer may not hit again should be returned as
+// apperr.Transient. The pool also marks two plain errors transient (see
+// executorErr): a deadline the executor set itself, e.g. a lock wait, that
+// expired while the caller still waits, and an error reporting Temporary() true.
+type Executor func(ctx context.Context, cmd inbound.TransferCommand) (inbound.TransferResult, error)
+
+// Pool is a fixed-size worker pool with weighted priority class queues.
+// It is safe for concurrent use.
+type Pool struct {
+	exec Executor
+	cfg  Config
+
+	mu      sync.Mutex
+	cond    *sync.Cond // signalled on enqueue and on stop; uses mu
+	queues  []*classQueue
+	byClass map[outbound.PriorityClass]*classQueue
+	def     *classQueue
+	closed  bool
+
+	busySince time.Time     // when the queues last became non-empty; zero while empty
+	svcTime   time.Duration // smoothed run time of executed jobs (EWMA 1/8)
+
+	active   atomic.Int64
+	running  sync.WaitGroup // jobs taken by a worker; Add under mu
+	rejected atomic.Int64
+	shed     atomic.Int64
+	stopOnce sync.Once
+}
+
+// New creates a Pool and starts its workers. Provide a context that is cancelled
+// on server shutdown; queued jobs are still drained, new submissions are rejected.
+func New(ctx context.Context, cfg Config, exec Executor) *Pool {
+	if cfg.Workers <= 0 {
+		cfg.Workers = runtime.NumCPU()
+	}
+	if len(cfg.Classes) == 0 {
+		cfg.Classes = DefaultClasses
+	}
+	if cfg.Shedding.Interval <= 0 {
+		cfg.Shedding.Interval = 100 * time.Millisecond
+	}
+	if cfg.Shedding.RetryAfter <= 0 {
+		cfg.Shedding.RetryAfter = time.Second
+	}
+	maxWeight := 1
+	for _, cc := range cfg.Classes {
+		maxWeight = max(maxWeight, cc.Weight)
+	}
+
+	p := &Pool{
+		exec:    exec,
+		cfg:     cfg,
+		byClass: make(map[outbound.PriorityClass]*classQueue, len(cfg.Classes)),
+	}
+	p.cond = sync.NewCond(&p.mu)
+	for _, cc := range cfg.Classes {
+		if cc.Weight <= 0 {
+			cc.Weight = 1
+		}
+		if cc.QueueSize <= 0 {
+			cc.QueueSize = 256
+		}
+		q := &classQueue{cfg: cc, jobs: make([]*job, 0, cc.QueueSize)}
+		if cfg.Shedding.Target > 0 {
+			q.target = cfg.Shedding.Target * time.Duration(cc.Weight) / time.Duration(maxWeight)
+		}
+		p.queues = append(p.queues, q)
+		p.byClass[cc.Class] = q
+	}
+	p.def = p.queues[0]
+	if q, ok := p.byClass[cfg.DefaultClass]; ok {
+		p.def = q
+	}
+
+	for i := 0; i < cfg.Workers; i++ {
+		go p.worker()
+	}
+	go func() {
+		<-ctx.Done()
+		p.Stop()
+	}()
+	return p
+}
+
+// Stop rejects new submissions and lets workers exit once the queues are drained.
+// Use Drain instead to hand queued jobs back rather than run them.
+func (p *Pool) Stop() {
+	p.stopOnce.Do(func() {
+		p.mu.Lock()
+		p.closed = true
+		p.mu.Unlock()
+		p.cond.Broadcast()
+	})
+}
+
+// Drain implements outbound.Drainer. Queued jobs are handed back without running
+// and their callers told they were accepted, except jobs whose callers already
+// gave up, which are dropped (see job.handBack); jobs already on a worker get
+// until ctx ends to finish.
+func (p *Pool) Drain(ctx context.Context) ([]outbound.PendingJob, error) {
+	p.mu.Lock()
+	p.closed = true
+	var pending []outbound.PendingJob
+	for _, q := range p.queues {
+		for len(q.jobs) > 0 {
+			if pj, ok := q.pop().handBack(q.cfg.Class); ok { // done is buffered
+				pending = append(pending, pj)
+			}
+		}
+	}
+	p.mu.Unlock()
+	p.cond.Broadcast()
+
+	idle := make(chan struct{})
+	go func() {
+		p.running.Wait()
+		close(idle)
+	}()
+	select {
+	case <-idle:
+		return sortPending(pending), nil
+	case <-ctx.Done():
+		return sortPending(pending), fmt.Errorf("%d jobs still running: %w", p.active.Load(), ctx.Err())
+	}
+}
+
+// Submit implements outbound.Dispatcher.
+// The job is queued in the class carried by ctx (see outbound.WithPriority) and the
+// caller waits for a worker's result or for ctx to end, whichever comes first.
+func (p *Pool) Submit(ctx context.Context, cmd inbound.TransferCommand) (inbound.TransferResult, error) {
+	j := newJob(ctx, cmd)
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return inbound.TransferResult{}, shutdownErr()
+	}
+	q := p.queueFor(ctx)
+	if !q.push(j) {
+		p.mu.Unlock()
+		p.rejected.Add(1)
+		return inbound.TransferResult{}, apperr.Overloaded(fmt.Sprintf("dispatcher queue full (%s)", q.cfg.Class))
+	}
+	if p.busySince.IsZero() {
+		p.busySince = j.queuedAt
+	}
+	p.mu.Unlock()
+	p.cond.Signal()
+
+	return await(ctx, j)
+}
+
+// QueueDepth implements outbound.Dispatcher.
+func (p *Pool) QueueDepth() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var n int64
+	for _, q := range p.queues {
+		n += int64(len(q.jobs))
+	}
+	return n
+}
+
+// QueueDepthByClass implements outbound.Dispatcher.
+func (p *Pool) QueueDepthByClass() map[outbound.PriorityClass]int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make(map[outbound.PriorityClass]int64, len(p.queues))
+	for _, q := range p.queues {
+		out[q.cfg.Class] = int64(len(q.jobs))
+	}
+	return out
+}
+
+// ActiveWorkers implements outbound.Dispatcher.
+func (p *Pool) ActiveWorkers() int64 { return p.active.Load() }
+
+// Stats is a point-in-time view of the pool for observability.
+type Stats struct {
+	Workers       int
+	ActiveWorkers int64
+	QueueDepth    int64
+	QueueCapacity int64
+	Rejected      int64 // submissions refused because the class queue was full
+	Shed          int64 // queued jobs dropped under overload or too close to their deadline
+}
+
+// Stats returns a snapshot for observability.
+func (p *Pool) Stats() Stats {
+	st := Stats{
+		Workers:       p.cfg.Workers,
+		ActiveWorkers: p.active.Load(),
+		Rejected:      p.rejected.Load(),
+		Shed:          p.shed.Load(),
+	}
+	p.mu.Lock()
+	for _, q := range p.queues {
+		st.QueueDepth += int64(len(q.jobs))
+		st.QueueCapacity += int64(q.cfg.QueueSize)
+	}
+	p.mu.Unlock()
+	return st
+}
+
+// queueFor resolves the class queue for ctx, falling back to the default class.
+// Caller must hold p.mu.
+func (p *Pool) queueFor(ctx context.Context) *classQueue {
+	if class, ok := outbound.PriorityFrom(ctx); ok {
+		if q, ok := p.byClass[class]; ok {
+			return q
+		}
+	}
+	return p.def
+}
+
+// worker pulls jobs until the pool is stopped and drained.
+func (p *Pool) worker() {
+	var took time.Duration // run time of the previous job, folded in under p.mu
+	for {
+		p.mu.Lock()
+		p.observe(took)
+		j := p.next()
+		for j == nil && !p.closed {
+			p.cond.Wait()
+			j = p.next()
+		}
+		if j != nil {
+			p.running.Add(1)
+		}
+		p.mu.Unlock()
+
+		if j == nil {
+			return
+		}
+		took = p.run(j)
+		p.running.Done()
+	}
+}
+
+// next picks the job to run, shedding on the way when shedding is enabled.
+// Caller must hold p.mu.
+func (p *Pool) next() *job {
+	shedding := p.cfg.Shedding.Target > 0
+	now := time.Now()
+	overloaded := shedding && !p.busySince.IsZero() && now.Sub(p.busySince) >= p.cfg.Shedding.Interval
+
+	if overloaded {
+		// Lighter classes have shorter targets, so their stale jobs go first.
+		for _, q := range p.queues {
+			for q.expired(now) {
+				p.drop(q.pop(), fmt.Sprintf("queued longer than %s (%s)", q.target, q.cfg.Class))
+			}
+		}
+	}
+	for {
+		j := nextJob(p.queues, overloaded)
+		if p.empty() {
+			p.busySince = time.Time{}
+		}
+		if j == nil {
+			return nil
+		}
+		if shedding && p.tooLate(j, now) {
+			p.drop(j, "deadline too close to run")
+			continue
+		}
+		return j
+	}
+}
+
+// tooLate reports whether j's deadline leaves less than a typical run. Caller must hold p.mu.
+func (p *Pool) tooLate(j *job, now time.Time) bool {
+	dl, ok := j.ctx.Deadline()
+	return ok && dl.Sub(now) < p.svcTime
+}
+
+// drop sheds j; done is buffered, so this never blocks under p.mu.
+func (p *Pool) drop(j *job, reason string) {
+	p.shed.Add(1)
+	j.done <- outcome{err: shedErr(reason, p.cfg.Shedding.RetryAfter)}
+}
+
+// empty reports whether every class queue is empty. Caller must hold p.mu.
+func (p *Pool) empty() bool {
+	for _, q := range p.queues {
+		if len(q.jobs) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// observe folds one run time into svcTime. Caller must hold p.mu.
+func (p *Pool) observe(took time.Duration) {
+	if took <= 0 {
+		return
+	}
+	if p.svcTime == 0 {
+		p.svcTime = took
+		return
+	}
+	p.svcTime += (took - p.svcTime) / 8
+}
+
+// run executes one job, hands the outcome back to its caller and reports how long
+// the executor took (zero when the job was skipped).
+func (p *Pool) run(j *job) time.Duration {
+	// The caller already gave up; executing now would commit work nobody waits for.
+	if err := j.ctx.Err(); err != nil {
+		j.done <- outcome{err: err}
+		return 0
+	}
+
+	p.active.Add(1)
+	defer p.active.Add(-1)
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			j.done <- outcome{err: apperr.Wrap(apperr.CodeInternal, "worker panic", fmt.Errorf("%v", rec))}
+		}
+	}()
+
+	start := time.Now()
+	res, err := p.exec(j.ctx, j.cmd)
+	j.done <- outcome{res: res, err: executorErr(j.ctx, err)}
+	return time.Since(start)
+}
@@ -0,0 +1,243 @@
+package worker_pool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+// temporary is a downstream error in the net.Error style.
+type temporary struct{ error }
+
+func (temporary) Temporary() bool { return true }
+
+func TestPoolMarksExecutorTransientFailures(t *testing.T) {
+	final := apperr.Invalid("insufficient funds")
+	for name, tc := range map[string]struct {
+		err       error
+		transient bool
+	}{
+		"own deadline":  {context.DeadlineExceeded, true},
+		"temporary":     {temporary{errors.New("connection reset")}, true},
+		"already typed": {apperr.Transient("leader changed", nil), true},
+		"final":         {final, false},
+		"plain":         {errors.New("boom"), false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p := New(t.Context(), Config{Workers: 1}, func(context.Context, inbound.TransferCommand) (inbound.TransferResult, error) {
+				return inbound.TransferResult{}, tc.err
+			})
+			t.Cleanup(p.Stop)
+
+			_, err := p.Submit(t.Context(), inbound.NewTransferCommand("a", "b", 100, "k1"))
+			if got := apperr.IsTransient(err); got != tc.transient {
+				t.Fatalf("IsTransient(%v) = %v, want %v", err, got, tc.transient)
+			}
+			if err != tc.err && apperr.As(err).Err != tc.err {
+				t.Fatalf("err = %v, want %v or a wrap of it", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestPoolPassesCallerCancellationThrough(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	p := New(t.Context(), Config{Workers: 1}, func(context.Context, inbound.TransferCommand) (inbound.TransferResult, error) {
+		cancel()
+		return inbound.TransferResult{}, context.Canceled
+	})
+	t.Cleanup(p.Stop)
+
+	if _, err := p.Submit(ctx, inbound.NewTransferCommand("a", "b", 100, "k1")); apperr.IsTransient(err) {
+		t.Fatalf("a caller that gave up got a retryable error: %v", err)
+	}
+}
+
+func TestPoolDrainAnswersQueuedCallersAccepted(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	p := New(t.Context(), Config{Workers: 1}, func(context.Context, inbound.TransferCommand) (inbound.TransferResult, error) {
+		started <- struct{}{}
+		<-release
+		return inbound.TransferResult{}, nil
+	})
+	t.Cleanup(p.Stop)
+
+	go p.Submit(t.Context(), inbound.NewTransferCommand("a", "b", 100, "k1"))
+	<-started
+	type answer struct {
+		res inbound.TransferResult
+		err error
+	}
+	queued := make(chan answer, 1)
+	go func() {
+		res, err := p.Submit(t.Context(), inbound.NewTransferCommand("a", "b", 200, "k2"))
+		queued <- answer{res, err}
+	}()
+	for p.QueueDepth() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	time.AfterFunc(10*time.Millisecond, func() { close(release) })
+	pending, err := p.Drain(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].IdempotencyKey != "k2" {
+		t.Fatalf("handed back %+v, want the queued job k2", pending)
+	}
+	got := <-queued
+	if got.err != nil || !got.res.Accepted() {
+		t.Fatalf("queued caller got %v, %v; want an accepted result", got.res.Status(), got.err)
+	}
+	if got.res.TransactionID() != pending[0].TransactionID || pending[0].TransactionID == uuid.Nil {
+		t.Fatalf("caller polls %v, job resumes as %v", got.res.TransactionID(), pending[0].TransactionID)
+	}
+	if _, err := p.Submit(t.Context(), inbound.NewTransferCommand("a", "b", 300, "k3")); apperr.As(err).Code != apperr.CodeOverloaded {
+		t.Fatalf("submission after drain: %v, want overloaded", err)
+	}
+}
+
+func TestPoolDrainDropsJobsWhoseCallerGaveUp(t *testing.T) {
+	p := heldPool(t, Config{})
+	gone, leave := context.WithCancel(t.Context())
+	answered := make(chan error, 2)
+	for _, tc := range []struct {
+		ctx context.Context
+		key string
+	}{{gone, "gone"}, {t.Context(), "waiting"}} {
+		go func() {
+			_, err := p.Submit(tc.ctx, inbound.NewTransferCommand("a", "b", 100, tc.key))
+			answered <- err
+		}()
+		for n := p.QueueDepth(); n == 0 || (tc.key == "waiting" && n < 2); n = p.QueueDepth() {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	leave()
+	if err := <-answered; err == nil {
+		t.Fatal("a caller that left got no error")
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	pending, _ := p.Drain(ctx) // the held job is still running
+	if len(pending) != 1 || pending[0].IdempotencyKey != "waiting" {
+		t.Fatalf("handed back %+v, want only the job whose caller still waits", pending)
+	}
+	if err := <-answered; err != nil {
+		t.Fatalf("waiting caller got %v, want an accepted result", err)
+	}
+}
+
+// heldPool returns a one-worker pool whose worker is busy until the test ends, so
+// the test can arrange the queues and call next itself.
+func heldPool(t *testing.T, cfg Config) *Pool {
+	t.Helper()
+	cfg.Workers = 1
+	release := make(chan struct{})
+	p := New(t.Context(), cfg, func(_ context.Context, cmd inbound.TransferCommand) (inbound.TransferResult, error) {
+		if cmd.IdempotencyKey() == "held" {
+			<-release
+		}
+		return inbound.TransferResult{}, nil
+	})
+	go p.Submit(t.Context(), inbound.NewTransferCommand("a", "b", 100, "held"))
+	for p.ActiveWorkers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	t.Cleanup(func() { close(release); p.Stop() })
+	return p
+}
+
+func TestPoolShedsByClassTargetOnlyUnderStandingQueues(t *testing.T) {
+	shedding := ShedConfig{Target: 80 * time.Millisecond, Interval: 100 * time.Millisecond, RetryAfter: 2 * time.Second}
+	for name, tc := range map[string]struct {
+		class    outbound.PriorityClass
+		busyFor  time.Duration // how long the queues have been non-empty
+		waited   time.Duration
+		deadline time.Duration // 0 => none
+		svcTime  time.Duration
+		shed     bool
+	}{
+		// Targets scale with weight: interactive 80ms, bulk 30ms, internal 10ms.
+		"burst, long wait":            {class: outbound.PriorityBulk, busyFor: 50 * time.Millisecond, waited: time.Second},
+		"standing, within target":     {class: outbound.PriorityInteractive, busyFor: 200 * time.Millisecond, waited: 50 * time.Millisecond},
+		"standing, past target":       {class: outbound.PriorityBulk, busyFor: 200 * time.Millisecond, waited: 50 * time.Millisecond, shed: true},
+		"standing, lighter class":     {class: outbound.PriorityInternal, busyFor: 200 * time.Millisecond, waited: 20 * time.Millisecond, shed: true},
+		"deadline shorter than a run": {class: outbound.PriorityInteractive, deadline: 10 * time.Millisecond, svcTime: 50 * time.Millisecond, shed: true},
+		"deadline longer than a run":  {class: outbound.PriorityInteractive, deadline: time.Second, svcTime: 50 * time.Millisecond},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p := heldPool(t, Config{Shedding: shedding})
+			ctx := outbound.WithPriority(t.Context(), tc.class)
+			if tc.deadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.deadline)
+				defer cancel()
+			}
+			j := newJob(ctx, inbound.NewTransferCommand("a", "b", 100, "k1"))
+			now := time.Now()
+			j.queuedAt = now.Add(-tc.waited)
+
+			p.mu.Lock()
+			p.svcTime = tc.svcTime
+			if tc.busyFor > 0 {
+				p.busySince = now.Add(-tc.busyFor)
+			}
+			p.byClass[tc.class].push(j)
+			got := p.next()
+			p.mu.Unlock()
+
+			if !tc.shed {
+				if got != j {
+					t.Fatalf("next = %v, want the queued job", got)
+				}
+				return
+			}
+			if got != nil {
+				t.Fatal("next returned a job that should have been shed")
+			}
+			err := (<-j.done).err
+			if e := apperr.As(err); e.Code != apperr.CodeOverloaded || e.RetryAfter != shedding.RetryAfter {
+				t.Fatalf("shed caller got %v (retry after %v), want overloaded after %v", err, e.RetryAfter, shedding.RetryAfter)
+			}
+			if st := p.Stats(); st.Shed != 1 {
+				t.Fatalf("Stats().Shed = %d, want 1", st.Shed)
+			}
+		})
+	}
+}
+
+func TestPoolServesNewestFirstUnderOverload(t *testing.T) {
+	for name, tc := range map[string]struct {
+		busyFor time.Duration
+		want    string
+	}{
+		"burst":          {50 * time.Millisecond, "old"},
+		"standing queue": {200 * time.Millisecond, "new"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p := heldPool(t, Config{Shedding: ShedConfig{Target: time.Hour, Interval: 100 * time.Millisecond}})
+			now := time.Now()
+
+			p.mu.Lock()
+			p.busySince = now.Add(-tc.busyFor)
+			for _, key := range []string{"old", "new"} {
+				p.def.push(newJob(t.Context(), inbound.NewTransferCommand("a", "b", 100, key)))
+			}
+			got := p.next()
+			p.mu.Unlock()
+
+			if got == nil || got.cmd.IdempotencyKey() != tc.want {
+				t.Fatalf("next = %v, want job %q", got, tc.want)
+			}
+		})
+	}
+}
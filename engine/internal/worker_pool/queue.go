@@ -0,0 +1,218 @@
+package worker_pool
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+	"fintech-capstone/m/v2/internal/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+// job is a queued transfer waiting for a worker.
+type job struct {
+	ctx      context.Context
+	cmd      inbound.TransferCommand
+	seq      uint64 // submission order, shared by every layer the job passes through
+	queuedAt time.Time
+	done     chan outcome // buffered(1): workers never block on abandoned callers
+}
+
+// lastSeq numbers jobs across all dispatchers in the process.
+var lastSeq atomic.Uint64
+
+type seqKey struct{}
+
+// newJob creates a job for cmd. A job handed on by an outer dispatcher (Mailboxes)
+// keeps the sequence number it was accepted with, so drained jobs sort in the order
+// callers submitted them.
+func newJob(ctx context.Context, cmd inbound.TransferCommand) *job {
+	seq, ok := ctx.Value(seqKey{}).(uint64)
+	if !ok {
+		seq = lastSeq.Add(1)
+		ctx = context.WithValue(ctx, seqKey{}, seq)
+	}
+	return &job{ctx: ctx, cmd: cmd, seq: seq, queuedAt: time.Now(), done: make(chan outcome, 1)}
+}
+
+// pending converts a job that was never started into an outbound.PendingJob.
+func (j *job) pending(class outbound.PriorityClass) outbound.PendingJob {
+	if c, ok := outbound.PriorityFrom(j.ctx); ok {
+		class = c
+	}
+	id, _ := outbound.TransactionIDFrom(j.ctx) // uuid.Nil when not assigned up front
+	client, _ := outbound.ClientIDFrom(j.ctx)
+	return outbound.PendingJob{
+		Seq:            j.seq,
+		FromAccount:    j.cmd.FromAccount(),
+		ToAccount:      j.cmd.ToAccount(),
+		AmountCents:    j.cmd.AmountCents(),
+		IdempotencyKey: j.cmd.IdempotencyKey(),
+		ClientID:       client,
+		Priority:       class,
+		TransactionID:  id,
+		QueuedAt:       j.queuedAt.UTC(),
+	}
+}
+
+// handBack converts a job that will not run before shutdown into a PendingJob and
+// answers its caller that the transfer was accepted: it resumes after restart under
+// the returned job's transaction ID (assigned here unless set up front), which the
+// caller polls instead of submitting again.
+//
+// A job whose caller already gave up is not handed back (ok is false): the caller
+// was answered, e.g. with a 504, so the transfer must not commit after restart. It
+// gets its context's error, as a worker would give it.
+func (j *job) handBack(class outbound.PriorityClass) (pj outbound.PendingJob, ok bool) {
+	if err := j.ctx.Err(); err != nil {
+		j.done <- outcome{err: err}
+		return outbound.PendingJob{}, false
+	}
+	pj = j.pending(class)
+	if pj.TransactionID == uuid.Nil {
+		pj.TransactionID = uuid.New()
+	}
+	j.done <- outcome{res: inbound.NewTransferResult(pj.TransactionID, inbound.ResultStatusAccepted, "queued; resumes after restart")}
+	return pj, true
+}
+
+// errShutdown marks jobs a stopped dispatcher refused without running.
+var errShutdown = errors.New("dispatcher shut down")
+
+// shutdownErr is what a caller receives when a stopped dispatcher refuses its job.
+func shutdownErr() error {
+	return apperr.Wrap(apperr.CodeOverloaded, "dispatcher shutting down", errShutdown)
+}
+
+// notRun reports whether err says the job was refused unstarted.
+func notRun(err error) bool {
+	return err != nil && errors.Is(apperr.As(err).Err, errShutdown)
+}
+
+// shedErr is what a caller receives when its job was dropped from an overloaded
+// queue. It is retryable: the hint tells the client when to come back.
+func shedErr(reason string, retryAfter time.Duration) error {
+	return apperr.Overloaded("dispatcher overloaded: " + reason).WithRetryAfter(retryAfter)
+}
+
+// executorErr marks the executor's transient failures with apperr.Transient, so
+// the retry stage may run the transfer again. An executor's own deadline (lock
+// wait, downstream call) that expired while ctx is still live is transient; so is
+// an error reporting Temporary() true, the convention of net errors. Everything
+// else, including ctx's own cancellation, is passed through unchanged.
+func executorErr(ctx context.Context, err error) error {
+	if err == nil || apperr.IsTransient(err) {
+		return err
+	}
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		return apperr.Transient("transfer executor timed out", err)
+	}
+	var tmp interface{ Temporary() bool }
+	if errors.As(err, &tmp) && tmp.Temporary() {
+		return apperr.Transient("transfer executor failed temporarily", err)
+	}
+	return err
+}
+
+// sortPending orders drained jobs by submission and drops duplicates reported by
+// more than one layer (e.g. a Mailboxes job handed back by its inner Pool).
+func sortPending(jobs []outbound.PendingJob) []outbound.PendingJob {
+	sort.Slice(jobs, func(i, k int) bool { return jobs[i].Seq < jobs[k].Seq })
+	out := jobs[:0]
+	for _, pj := range jobs {
+		if len(out) > 0 && out[len(out)-1].Seq == pj.Seq {
+			continue
+		}
+		out = append(out, pj)
+	}
+	return out
+}
+
+// outcome is what a worker hands back to the waiting Submit caller.
+type outcome struct {
+	res inbound.TransferResult
+	err error
+}
+
+// await blocks until a worker hands back the job's outcome or the caller's ctx ends.
+func await(ctx context.Context, j *job) (inbound.TransferResult, error) {
+	select {
+	case out := <-j.done:
+		return out.res, out.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return inbound.TransferResult{}, apperr.Timeout("processing timeout")
+		}
+		return inbound.TransferResult{}, apperr.Internal("request canceled")
+	}
+}
+
+// classQueue is a bounded FIFO for one priority class.
+// All fields are guarded by Pool.mu.
+type classQueue struct {
+	cfg     ClassConfig
+	target  time.Duration // longest wait under overload; 0 => never shed on wait
+	jobs    []*job
+	current int // smooth weighted round-robin credit
+}
+
+func (q *classQueue) push(j *job) bool {
+	if len(q.jobs) >= q.cfg.QueueSize {
+		return false
+	}
+	q.jobs = append(q.jobs, j)
+	return true
+}
+
+func (q *classQueue) pop() *job {
+	j := q.jobs[0]
+	q.jobs[0] = nil // let the GC reclaim the job once done
+	q.jobs = q.jobs[1:]
+	return j
+}
+
+// popNewest takes the most recently queued job (adaptive LIFO under overload).
+func (q *classQueue) popNewest() *job {
+	last := len(q.jobs) - 1
+	j := q.jobs[last]
+	q.jobs[last] = nil
+	q.jobs = q.jobs[:last]
+	return j
+}
+
+// expired reports whether the oldest job waited past the class target.
+func (q *classQueue) expired(now time.Time) bool {
+	return q.target > 0 && len(q.jobs) > 0 && now.Sub(q.jobs[0].queuedAt) > q.target
+}
+
+// nextJob picks the next job using smooth weighted round-robin over busy classes.
+// Every busy class gains its weight in credit per pick and the winner pays back the
+// total, so over time each class gets weight/total of the picks and none starves.
+// newest takes the class's latest job instead of its oldest. Caller must hold Pool.mu.
+func nextJob(queues []*classQueue, newest bool) *job {
+	var best *classQueue
+	total := 0
+	for _, q := range queues {
+		if len(q.jobs) == 0 {
+			continue
+		}
+		q.current += q.cfg.Weight
+		total += q.cfg.Weight
+		if best == nil || q.current > best.current {
+			best = q
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	best.current -= total
+	if newest {
+		return best.popNewest()
+	}
+	return best.pop()
+}
@@ -0,0 +1,120 @@
+package worker_pool
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+	"fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+)
+
+func TestNextJobServesBusyClassesByWeight(t *testing.T) {
+	for name, tc := range map[string]struct {
+		weights map[outbound.PriorityClass]int // 0 => class has no jobs
+		picks   int
+		want    map[outbound.PriorityClass]int
+	}{
+		"all busy": {
+			map[outbound.PriorityClass]int{"interactive": 8, "bulk": 3, "internal": 1}, 24,
+			map[outbound.PriorityClass]int{"interactive": 16, "bulk": 6, "internal": 2},
+		},
+		"idle class earns no credit": {
+			map[outbound.PriorityClass]int{"interactive": 0, "bulk": 3, "internal": 1}, 8,
+			map[outbound.PriorityClass]int{"bulk": 6, "internal": 2},
+		},
+		"equal weights": {
+			map[outbound.PriorityClass]int{"interactive": 1, "bulk": 1}, 10,
+			map[outbound.PriorityClass]int{"interactive": 5, "bulk": 5},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var queues []*classQueue
+			for _, class := range []outbound.PriorityClass{"interactive", "bulk", "internal"} {
+				w, ok := tc.weights[class]
+				if !ok {
+					continue
+				}
+				q := &classQueue{cfg: ClassConfig{Class: class, Weight: max(w, 1), QueueSize: tc.picks}}
+				for i := 0; w > 0 && i < tc.picks; i++ {
+					q.push(newJob(outbound.WithPriority(t.Context(), class), inbound.NewTransferCommand("a", "b", 100, "k")))
+				}
+				queues = append(queues, q)
+			}
+
+			got := make(map[outbound.PriorityClass]int)
+			for i := 0; i < tc.picks; i++ {
+				j := nextJob(queues, false)
+				if j == nil {
+					t.Fatalf("pick %d found no job", i)
+				}
+				class, _ := outbound.PriorityFrom(j.ctx)
+				got[class]++
+			}
+			for class, n := range tc.want {
+				if got[class] != n {
+					t.Fatalf("picks = %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestPoolRunsQueuedClassesByWeight(t *testing.T) {
+	const perClass = 12
+	gate := make(chan struct{})
+	var (
+		mu  sync.Mutex
+		ran []outbound.PriorityClass
+	)
+	p := New(t.Context(), Config{Workers: 1}, func(ctx context.Context, cmd inbound.TransferCommand) (inbound.TransferResult, error) {
+		if cmd.IdempotencyKey() == "blocker" {
+			<-gate
+			return inbound.TransferResult{}, nil
+		}
+		class, _ := outbound.PriorityFrom(ctx)
+		mu.Lock()
+		ran = append(ran, class)
+		mu.Unlock()
+		return inbound.TransferResult{}, nil
+	})
+	t.Cleanup(p.Stop)
+
+	// One worker, held busy while every class fills up.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		p.Submit(t.Context(), inbound.NewTransferCommand("a", "b", 100, "blocker"))
+	}()
+	for p.ActiveWorkers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for _, class := range []outbound.PriorityClass{outbound.PriorityInternal, outbound.PriorityBulk, outbound.PriorityInteractive} {
+		for i := 0; i < perClass; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				p.Submit(outbound.WithPriority(t.Context(), class), inbound.NewTransferCommand("a", "b", 100, "k"))
+			}()
+		}
+	}
+	for p.QueueDepth() < 3*perClass {
+		time.Sleep(time.Millisecond)
+	}
+	close(gate)
+	wg.Wait()
+
+	// DefaultClasses weigh interactive 8 : bulk 3 : internal 1 per 12 picks.
+	got := make(map[outbound.PriorityClass]int)
+	for _, class := range ran[:12] {
+		got[class]++
+	}
+	if got[outbound.PriorityInteractive] != 8 || got[outbound.PriorityBulk] != 3 || got[outbound.PriorityInternal] != 1 {
+		t.Fatalf("first 12 runs by class = %v, want interactive 8, bulk 3, internal 1", got)
+	}
+	if len(ran) != 3*perClass {
+		t.Fatalf("ran %d jobs, want %d", len(ran), 3*perClass)
+	}
+}
@@ -0,0 +1,39 @@
+package worker_pool
+
+import (
+	"hash/fnv"
+	"strings"
+
+	"fintech-capstone/m/v2/internal/api_gateway/ports/inbound"
+)
+
+// Segmenter assigns a transfer to a bulkhead by name (hash range, account type, tenant...).
+// Returning a name with no configured bulkhead routes the job to the fallback bulkhead.
+type Segmenter func(cmd inbound.TransferCommand) string
+
+// HashSegments spreads source accounts evenly across the named bulkheads,
+// so one hot account can only exhaust its own segment.
+func HashSegments(names ...string) Segmenter {
+	return func(cmd inbound.TransferCommand) string {
+		if len(names) == 0 {
+			return ""
+		}
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(cmd.FromAccount()))
+		return names[h.Sum32()%uint32(len(names))]
+	}
+}
+
+// PrefixSegments routes by source account ID prefix (e.g. account type or tenant
+// encoded as "MER-", "FEE-"). The longest matching prefix wins; no match => fallback.
+func PrefixSegments(prefixes map[string]string, fallback string) Segmenter {
+	return func(cmd inbound.TransferCommand) string {
+		best, name := -1, fallback
+		for prefix, seg := range prefixes {
+			if len(prefix) > best && strings.HasPrefix(cmd.FromAccount(), prefix) {
+				best, name = len(prefix), seg
+			}
+		}
+		return name
+	}
+}
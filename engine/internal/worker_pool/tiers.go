@@ -0,0 +1,24 @@
+package worker_pool
+
+import "fintech-capstone/m/v2/internal/api_gateway/ports/outbound"
+
+// Compile-time check that Tiers implements outbound.Prioritizer.
+var _ outbound.Prioritizer = Tiers{}
+
+// Tiers maps client IDs to priority classes.
+// It is read-only after construction and safe for concurrent use.
+type Tiers struct {
+	Clients map[string]outbound.PriorityClass
+	Default outbound.PriorityClass // "" => outbound.PriorityInteractive
+}
+
+// Classify implements outbound.Prioritizer.
+func (t Tiers) Classify(clientID string) outbound.PriorityClass {
+	if p, ok := t.Clients[clientID]; ok {
+		return p
+	}
+	if t.Default != "" {
+		return t.Default
+	}
+	return outbound.PriorityInteractive
+}